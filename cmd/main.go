@@ -3,32 +3,118 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/andrejsoucek/chronos/internal/action"
+	"github.com/andrejsoucek/chronos/internal/catalog"
+	"github.com/andrejsoucek/chronos/internal/config"
+	"github.com/andrejsoucek/chronos/internal/ui"
 	"github.com/andrejsoucek/chronos/pkg/clockify"
+	"github.com/andrejsoucek/chronos/pkg/datetimeutils"
 	"github.com/andrejsoucek/chronos/pkg/gitlab"
+	"github.com/andrejsoucek/chronos/pkg/harvest"
+	"github.com/andrejsoucek/chronos/pkg/httpretry"
 	"github.com/andrejsoucek/chronos/pkg/linear"
+	"github.com/andrejsoucek/chronos/pkg/toggl"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
 	"github.com/joho/godotenv"
 	"github.com/urfave/cli/v3"
 )
 
 func main() {
-	projectId, l, g, cify := loadConfiguration()
-	cmd := createCommands(projectId, l, g, cify)
+	cmd := createCommands()
 
-	if err := cmd.Run(context.Background(), os.Args); err != nil {
+	// Cancel in-flight requests when interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ctx = httpretry.WithObserver(ctx, func(e httpretry.RetryEvent) {
+		log.Print(e)
+	})
+
+	if err := cmd.Run(ctx, os.Args); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func loadConfiguration() (string, *linear.Linear, *gitlab.Gitlab, *clockify.Clockify) {
+// loadConfiguration creates the time tracker from the selected profile of the config file, the legacy .env file is
+// used when there is no config file.
+func loadConfiguration(ctx context.Context, profileName string) (string, tracker.TimeTracker, error) {
+	profile, err := loadProfile(profileName)
+	if err != nil {
+		return "", nil, err
+	}
+	return newClients(ctx, profile)
+}
+
+// newClients creates the time tracker of the profile and returns it with the default project.
+func newClients(ctx context.Context, profile *config.Profile) (string, tracker.TimeTracker, error) {
+	if err := profile.ResolveBackendSecret(ctx); err != nil {
+		return "", nil, err
+	}
+
+	t, err := newTimeTracker(profile, httpretry.NewClient())
+	if err != nil {
+		return "", nil, err
+	}
+
+	return profile.DefaultProject, t, nil
+}
+
+// newIntegrations creates the Linear and GitLab clients, their secrets are read only by the commands using them. An
+// integration whose secret cannot be read is left unconfigured and the error is kept to be shown in its place.
+func newIntegrations(ctx context.Context, profile *config.Profile) action.Integrations {
+	integrations := action.Integrations{
+		LinearErr: profile.ResolveLinearSecret(ctx),
+		GitlabErr: profile.ResolveGitlabSecret(ctx),
+	}
+
+	httpClient := httpretry.NewClient()
+
+	integrations.Linear = linear.NewLinear(&linear.LinearConfig{
+		APIKey:     profile.Linear.APIKey,
+		BaseURL:    profile.Linear.BaseURL,
+		HTTPClient: httpClient,
+	})
+
+	integrations.Gitlab = gitlab.NewGitlab(&gitlab.GitlabConfig{
+		APIKey:     profile.Gitlab.AccessToken,
+		BaseURL:    profile.Gitlab.BaseURL,
+		UserID:     profile.Gitlab.UserID,
+		HTTPClient: httpClient,
+	})
+
+	return integrations
+}
+
+func loadProfile(profileName string) (*config.Profile, error) {
+	configPath, err := config.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load(configPath)
+	if err == nil {
+		return cfg.Profile(profileName)
+	}
+	if !errors.Is(err, config.ErrNotFound) {
+		return nil, err
+	}
+	if profileName != "" {
+		return nil, fmt.Errorf("profile '%s' cannot be used, config file %s does not exist", profileName, configPath)
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		log.Fatal("Error getting user home directory", err)
+		return nil, fmt.Errorf("error getting user home directory: %v", err)
 	}
 
 	homeEnvPath := filepath.Join(homeDir, ".chronos", ".env")
@@ -36,46 +122,86 @@ func loadConfiguration() (string, *linear.Linear, *gitlab.Gitlab, *clockify.Cloc
 	if err != nil {
 		err = godotenv.Load(".env")
 		if err != nil {
-			log.Fatal("Error loading .env file: neither", homeEnvPath, "nor .env could be loaded:", err)
+			return nil, fmt.Errorf("no configuration found: neither %s, %s nor .env could be loaded: %v", configPath, homeEnvPath, err)
 		}
 	}
 
-	projectId := os.Getenv("CLOCKIFY_DEFAULT_PROJECT")
-
-	l := linear.NewLinear(&linear.LinearConfig{
-		APIKey:  os.Getenv("LINEAR_API_KEY"),
-		BaseURL: os.Getenv("LINEAR_BASE_URL"),
-	})
-
-	g := gitlab.NewGitlab(&gitlab.GitlabConfig{
-		APIKey:  os.Getenv("GITLAB_ACCESS_TOKEN"),
-		BaseURL: os.Getenv("GITLAB_BASE_URL"),
-		UserID:  os.Getenv("GITLAB_USER_ID"),
-	})
-
-	cify := clockify.NewClockify(&clockify.ClockifyConfig{
-		APIKey:      os.Getenv("CLOCKIFY_API_KEY"),
-		BaseURL:     os.Getenv("CLOCKIFY_BASE_URL"),
-		UserURL:     os.Getenv("CLOCKIFY_USER_URL"),
-		WorkspaceID: os.Getenv("CLOCKIFY_WORKSPACE"),
-		UserID:      os.Getenv("CLOCKIFY_USER_ID"),
-	})
+	return config.FromEnv(), nil
+}
 
-	return projectId, l, g, cify
+// newTimeTracker creates the time tracking backend selected in the profile.
+func newTimeTracker(profile *config.Profile, httpClient *http.Client) (tracker.TimeTracker, error) {
+	switch profile.Backend {
+	case "clockify":
+		return clockify.NewClockify(&clockify.ClockifyConfig{
+			APIKey:      profile.Clockify.APIKey,
+			BaseURL:     profile.Clockify.BaseURL,
+			UserURL:     profile.Clockify.UserURL,
+			WorkspaceID: profile.Clockify.WorkspaceID,
+			UserID:      profile.Clockify.UserID,
+			HTTPClient:  httpClient,
+		}), nil
+	case "toggl":
+		return toggl.NewToggl(&toggl.TogglConfig{
+			APIKey:      profile.Toggl.APIKey,
+			BaseURL:     profile.Toggl.BaseURL,
+			WorkspaceID: profile.Toggl.WorkspaceID,
+			HTTPClient:  httpClient,
+		}), nil
+	case "harvest":
+		return harvest.NewHarvest(&harvest.HarvestConfig{
+			AccessToken: profile.Harvest.AccessToken,
+			AccountID:   profile.Harvest.AccountID,
+			BaseURL:     profile.Harvest.BaseURL,
+			HTTPClient:  httpClient,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown time tracking backend '%s'", profile.Backend)
+	}
 }
 
-func createCommands(projectId string, l *linear.Linear, g *gitlab.Gitlab, cify *clockify.Clockify) *cli.Command {
+func createCommands() *cli.Command {
+	// The clients are created once the selected profile is known
+	var (
+		profile   *config.Profile
+		projectId string
+		t         tracker.TimeTracker
+	)
+
 	return &cli.Command{
 		Name:                  "chronos",
-		Usage:                 "A simple CLI tool to log time entries to Clockify",
+		Usage:                 "A simple CLI tool to log time entries to Clockify, Toggl Track or Harvest",
 		EnableShellCompletion: true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "profile",
+				Aliases:     []string{"p"},
+				Usage:       "profile of the config file to use",
+				DefaultText: "default_profile of the config file",
+				Sources:     cli.EnvVars("CHRONOS_PROFILE"),
+			},
+		},
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			// The config and completion commands work without a valid configuration
+			if first := cmd.Args().First(); first == "config" || first == "completion" {
+				return ctx, nil
+			}
+			var err error
+			profile, err = loadProfile(cmd.String("profile"))
+			if err != nil {
+				return ctx, err
+			}
+			projectId, t, err = newClients(ctx, profile)
+			return ctx, err
+		},
 		Commands: []*cli.Command{
+			configCommand(),
 			{
 				Name:    "workspace",
 				Aliases: []string{"ws"},
-				Usage:   "Get clockify workspace ID",
-				Action: func(context.Context, *cli.Command) error {
-					userInfo, err := action.GetWorkspaceID(cify)
+				Usage:   "Show the account and workspace information of the time tracker",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					userInfo, err := action.GetWorkspaceID(ctx, t)
 					if err != nil {
 						return err
 					}
@@ -86,8 +212,31 @@ func createCommands(projectId string, l *linear.Linear, g *gitlab.Gitlab, cify *
 			{
 				Name:      "log",
 				Aliases:   []string{"l"},
-				Usage:     "Log time entry to Clockify",
-				UsageText: "chronos log <duration> <task>",
+				Usage:     "Log a time entry",
+				UsageText: "chronos log [--date YYYY-MM-DD] [--start HH:MM] [--end HH:MM] [--at HH:MM-HH:MM] [--project NAME] [--task NAME] [--tag NAME] <duration> <task>",
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:        "date",
+						Aliases:     []string{"d"},
+						Usage:       "day of the entry (YYYY-MM-DD, today or yesterday)",
+						DefaultText: "today",
+					},
+					&cli.StringFlag{
+						Name:    "start",
+						Aliases: []string{"s"},
+						Usage:   "start time of the entry (HH:MM)",
+					},
+					&cli.StringFlag{
+						Name:    "end",
+						Aliases: []string{"e"},
+						Usage:   "end time of the entry (HH:MM)",
+					},
+					&cli.StringFlag{
+						Name:  "at",
+						Usage: "start and end time of the entry (HH:MM-HH:MM), the duration can be omitted",
+					},
+				}, entryFlags()...),
+				ShellComplete: completeEntryFlags,
 				Arguments: []cli.Argument{
 					&cli.StringArg{
 						Name: "duration",
@@ -97,49 +246,608 @@ func createCommands(projectId string, l *linear.Linear, g *gitlab.Gitlab, cify *
 					},
 				},
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					if cmd.StringArg("duration") == "" || cmd.StringArg("task") == "" {
-						return errors.New("both duration and task arguments are required")
+					durationArg := cmd.StringArg("duration")
+					task := cmd.StringArg("task")
+
+					projectId, details, err := entryDetails(ctx, cmd, t, profile, projectId)
+					if err != nil {
+						return err
+					}
+
+					if !hasIntervalFlags(cmd) {
+						if durationArg == "" || task == "" {
+							return errors.New("both duration and task arguments are required")
+						}
+						duration, err := time.ParseDuration(durationArg)
+						if err != nil {
+							return err
+						}
+						start, end, err := action.LogTime(ctx, t, projectId, duration, task, details)
+						if err != nil {
+							return err
+						}
+						log.Printf("Logged %s for task: %s", end.Sub(start), task)
+						return nil
 					}
-					duration, err := time.ParseDuration(cmd.StringArg("duration"))
+
+					// With both ends of the interval given the duration is optional
+					if task == "" {
+						task, durationArg = durationArg, ""
+					}
+					if task == "" {
+						return errors.New("task argument is required")
+					}
+
+					start, end, err := parseLogInterval(cmd, durationArg)
 					if err != nil {
 						return err
 					}
+					start, end, err = action.LogTimeInterval(ctx, t, projectId, start, end, task, details)
+					if err != nil {
+						return err
+					}
+					log.Printf("Logged %s (%s - %s) for task: %s", end.Sub(start), start.Format("2006-01-02 15:04"), end.Format("15:04"), task)
+					return nil
+				},
+			},
+			{
+				Name:          "start",
+				Usage:         "Start a timer for a task",
+				UsageText:     "chronos start [--project NAME] [--task NAME] [--tag NAME] <task>",
+				Flags:         entryFlags(),
+				ShellComplete: completeEntryFlags,
+				Arguments: []cli.Argument{
+					&cli.StringArg{
+						Name: "task",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
 					task := cmd.StringArg("task")
-					err = action.LogTime(cify, projectId, duration, task)
+					if task == "" {
+						return errors.New("task argument is required")
+					}
+					projectId, details, err := entryDetails(ctx, cmd, t, profile, projectId)
 					if err != nil {
 						return err
 					}
-					log.Printf("Logged %s for task: %s", duration, task)
+					if err := action.StartTimer(ctx, t, projectId, task, details); err != nil {
+						return err
+					}
+					log.Printf("Started timer for task: %s", task)
 					return nil
 				},
 			},
 			{
-				Name:    "report",
-				Aliases: []string{"r"},
-				Usage:   "Show a report of logged time entries",
-				Flags: []cli.Flag{
-					&cli.IntFlag{
-						Name:        "month",
-						Aliases:     []string{"m"},
-						DefaultText: "current month",
+				Name:  "stop",
+				Usage: "Stop the running timer",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					stopped, err := action.StopTimer(ctx, t)
+					if err != nil {
+						return err
+					}
+					log.Printf("Stopped timer after %s for task: %s", elapsed(stopped), stopped.Description)
+					return nil
+				},
+			},
+			{
+				Name:  "status",
+				Usage: "Show the running timer",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					running, err := action.GetTimerStatus(ctx, t)
+					if errors.Is(err, tracker.ErrNoRunningTimer) {
+						log.Print("No timer is running")
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+					log.Printf("Running for %s (since %s) for task: %s",
+						elapsed(running), running.TimeInterval.Start.Local().Format("15:04"), running.Description)
+					return nil
+				},
+			},
+			{
+				Name:          "switch",
+				Usage:         "Stop the running timer and start a new one for another task",
+				UsageText:     "chronos switch [--project NAME] [--task NAME] [--tag NAME] <task>",
+				Flags:         entryFlags(),
+				ShellComplete: completeEntryFlags,
+				Arguments: []cli.Argument{
+					&cli.StringArg{
+						Name: "task",
 					},
 				},
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					now := time.Now()
-					year, month, _ := now.Date()
-					if cmd.Int("month") != 0 {
-						month = time.Month(cmd.Int("month"))
+					task := cmd.StringArg("task")
+					if task == "" {
+						return errors.New("task argument is required")
+					}
+					projectId, details, err := entryDetails(ctx, cmd, t, profile, projectId)
+					if err != nil {
+						return err
+					}
+					stopped, err := action.SwitchTimer(ctx, t, projectId, task, details)
+					if err != nil {
+						return err
+					}
+					if stopped != nil {
+						log.Printf("Stopped timer after %s for task: %s", elapsed(stopped), stopped.Description)
+					}
+					log.Printf("Started timer for task: %s", task)
+					return nil
+				},
+			},
+			{
+				Name:      "report",
+				Aliases:   []string{"r"},
+				Usage:     "Show a report of logged time entries",
+				UsageText: "chronos report [--week] [--month M | --month YYYY-MM] [--year YYYY]",
+				Flags:     periodFlags("show a week with the start and end of the entries"),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if err := profile.Report.Validate(); err != nil {
+						return err
+					}
+					rounding, err := profile.RoundingPolicy()
+					if err != nil {
+						return err
+					}
+					schedule, err := profile.Schedule()
+					if err != nil {
+						return err
+					}
+					calendar, err := profile.Calendar()
+					if err != nil {
+						return err
+					}
+					reportConfig := ui.ReportConfig{
+						KeepEnd:      profile.Report.EditKeeps == "end",
+						WorkdayStart: profile.Report.WorkdayStart,
+						Rounding:     rounding,
+						Week:         cmd.Bool("week"),
+						Schedule:     schedule,
+						Calendar:     calendar,
 					}
 
-					firstOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
-					lastOfMonth := firstOfMonth.AddDate(0, 1, 0).Add(-time.Second) // Last second of the month
-					err := action.ShowReport(cify, l, g, projectId, firstOfMonth, lastOfMonth)
+					from, to, err := periodRange(cmd, time.Now())
+					if err != nil {
+						return err
+					}
+					err = action.ShowReport(ctx, t, newIntegrations(ctx, profile), projectId, reportConfig, from, to)
 					if err != nil {
 						return err
 					}
 					return nil
 				},
 			},
+			{
+				Name:      "balance",
+				Usage:     "Compare the logged time with the targets, up to today for the current month or week",
+				UsageText: "chronos balance [--week] [--month M | --month YYYY-MM] [--year YYYY] [--json]",
+				Flags: append(periodFlags("compare a week instead of a month"), &cli.BoolFlag{
+					Name:  "json",
+					Usage: "print the balance as JSON with the durations in seconds",
+				}),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					schedule, err := profile.Schedule()
+					if err != nil {
+						return err
+					}
+					if schedule == nil {
+						return errors.New("no targets are configured, add a targets section to the profile")
+					}
+					from, to, err := periodRange(cmd, time.Now())
+					if err != nil {
+						return err
+					}
+					return action.ShowBalance(ctx, os.Stdout, t, schedule, from, to, cmd.Bool("json"))
+				},
+			},
+			{
+				Name:      "holidays",
+				Usage:     "List the public holidays and leave days, the days without a target",
+				UsageText: "chronos holidays [--week] [--month M | --month YYYY-MM] [--year YYYY] [--json]",
+				Flags: append(periodFlags("list a week instead of a month"), &cli.BoolFlag{
+					Name:  "json",
+					Usage: "print the days as JSON",
+				}),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					calendar, err := profile.Calendar()
+					if err != nil {
+						return err
+					}
+					if calendar == nil {
+						return errors.New("no holidays are configured, add a holidays section to the profile")
+					}
+					from, to, err := periodRange(cmd, time.Now())
+					if err != nil {
+						return err
+					}
+					return action.ShowHolidays(os.Stdout, calendar, from, to, cmd.Bool("json"))
+				},
+			},
+			{
+				Name:      "check",
+				Usage:     "Find missing days, days off target, overlapping entries, entries spanning midnight or without a description",
+				UsageText: "chronos check [--week] [--month M | --month YYYY-MM] [--year YYYY] [--json]",
+				Description: "Checks the period up to today. Exits with 0 when there are no issues, with 2 when there are some " +
+					"and with 1 when the check fails.",
+				Flags: append(periodFlags("check a week instead of a month"), &cli.BoolFlag{
+					Name:  "json",
+					Usage: "print the issues as JSON",
+				}),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					schedule, err := profile.Schedule()
+					if err != nil {
+						return err
+					}
+					calendar, err := profile.Calendar()
+					if err != nil {
+						return err
+					}
+					from, to, err := periodRange(cmd, time.Now())
+					if err != nil {
+						return err
+					}
+					issues, err := action.Check(ctx, os.Stdout, t, schedule, calendar, from, to, cmd.Bool("json"))
+					if err != nil {
+						return err
+					}
+					if issues > 0 {
+						// The issues are printed already
+						return cli.Exit("", 2)
+					}
+					return nil
+				},
+			},
 		},
 	}
 }
+
+func configCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "Create, show or validate the configuration",
+		Commands: []*cli.Command{
+			{
+				Name:  "init",
+				Usage: "Create a profile of the config file interactively",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "clockify-url",
+						Usage:       "URL of a regional or self-hosted Clockify API, e.g. https://euc1.clockify.me/api/v1/",
+						DefaultText: "https://api.clockify.me/api/v1/",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					configPath, err := config.DefaultPath()
+					if err != nil {
+						return err
+					}
+					clockifyURL := cmd.String("clockify-url")
+					if clockifyURL != "" && !strings.HasSuffix(clockifyURL, "/") {
+						clockifyURL += "/"
+					}
+					return action.InitConfig(ctx, os.Stdin, os.Stdout, configPath, clockifyURL, httpretry.NewClient())
+				},
+			},
+			{
+				Name:  "show",
+				Usage: "Show the selected profile with the API keys hidden",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					profile, err := loadProfile(cmd.String("profile"))
+					if err != nil {
+						return err
+					}
+					return action.ShowConfig(os.Stdout, cmd.String("profile"), profile)
+				},
+			},
+			{
+				Name:  "validate",
+				Usage: "Check the selected profile and its credentials",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					profile, err := loadProfile(cmd.String("profile"))
+					if err != nil {
+						return err
+					}
+					_, t, err := newClients(ctx, profile)
+					if err != nil {
+						return err
+					}
+					return action.ValidateConfig(ctx, os.Stdout, profile, t, newIntegrations(ctx, profile))
+				},
+			},
+		},
+	}
+}
+
+// billableFlag adds --billable and --no-billable, the default of the project is used when neither is given.
+func billableFlag() cli.Flag {
+	return &cli.BoolWithInverseFlag{
+		Name:        "billable",
+		Usage:       "mark the entry as billable or not billable",
+		DefaultText: "project default",
+	}
+}
+
+// entryFlags are the flags setting the project, task, tags and billable flag of a new entry.
+func entryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "project",
+			Usage:       "project of the entry, a part of its name or its ID",
+			DefaultText: "default_project",
+		},
+		&cli.StringFlag{
+			Name:  "task",
+			Usage: "task of the project, a part of its name or its ID",
+		},
+		&cli.StringSliceFlag{
+			Name:  "tag",
+			Usage: "tag of the entry, a part of its name or its ID, can be repeated",
+		},
+		billableFlag(),
+	}
+}
+
+// entryDetails resolves the entry flags and the rounding of the entry, the project given by --project replaces the
+// default one.
+func entryDetails(ctx context.Context, cmd *cli.Command, t tracker.TimeTracker, profile *config.Profile, projectId string) (string, action.EntryDetails, error) {
+	rounding, err := profile.RoundingPolicy()
+	if err != nil {
+		return "", action.EntryDetails{}, err
+	}
+
+	var details action.EntryDetails
+	if cmd.IsSet("billable") {
+		billable := cmd.Bool("billable")
+		details.Billable = &billable
+	}
+	if !cmd.IsSet("project") && !cmd.IsSet("task") && !cmd.IsSet("tag") {
+		details.Rounding = rounding.For(projectId)
+		return projectId, details, nil
+	}
+
+	c, err := newCatalog(t)
+	if err != nil {
+		return "", details, err
+	}
+	if query := cmd.String("project"); query != "" {
+		project, err := c.Project(ctx, query)
+		if err != nil {
+			return "", details, err
+		}
+		projectId = project.ID
+	}
+	if query := cmd.String("task"); query != "" {
+		task, err := c.Task(ctx, projectId, query)
+		if err != nil {
+			return "", details, err
+		}
+		details.TaskID = task.ID
+	}
+	for _, query := range cmd.StringSlice("tag") {
+		tag, err := c.Tag(ctx, query)
+		if err != nil {
+			return "", details, err
+		}
+		details.TagIDs = append(details.TagIDs, tag.ID)
+	}
+	details.Rounding = rounding.For(projectId)
+	return projectId, details, nil
+}
+
+// newCatalog creates the lookup of project, task and tag names, which are only resolved via the Clockify API.
+func newCatalog(t tracker.TimeTracker) (*catalog.Catalog, error) {
+	c, ok := t.(*clockify.Clockify)
+	if !ok {
+		return nil, errors.New("--project, --task and --tag are supported with the Clockify backend only")
+	}
+	cachePath, err := catalog.DefaultCachePath(c.Config.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.New(c, cachePath), nil
+}
+
+// completeEntryFlags completes the names of projects, tasks and tags after the entry flags, anything else is
+// completed by urfave/cli. The completion runs before the Before hook, so the configuration is loaded here.
+func completeEntryFlags(ctx context.Context, cmd *cli.Command) {
+	// The arguments end with the completion flag, the one before it is the flag being completed
+	args := os.Args
+	if len(args) < 3 {
+		cli.DefaultCompleteWithFlags(ctx, cmd)
+		return
+	}
+	previous := args[len(args)-2]
+	if previous != "--project" && previous != "--task" && previous != "--tag" {
+		cli.DefaultCompleteWithFlags(ctx, cmd)
+		return
+	}
+
+	projectId, t, err := loadConfiguration(ctx, cmd.String("profile"))
+	if err != nil {
+		return
+	}
+	c, err := newCatalog(t)
+	if err != nil {
+		return
+	}
+
+	var names []string
+	switch previous {
+	case "--project":
+		names, err = c.ProjectNames(ctx)
+	case "--task":
+		if query := flagValue(args, "--project"); query != "" {
+			project, err := c.Project(ctx, query)
+			if err != nil {
+				return
+			}
+			projectId = project.ID
+		}
+		names, err = c.TaskNames(ctx, projectId)
+	case "--tag":
+		names, err = c.TagNames(ctx)
+	}
+	if err != nil {
+		return
+	}
+	for _, name := range names {
+		fmt.Fprintln(cmd.Root().Writer, name)
+	}
+}
+
+// flagValue returns the value of the flag given as "--flag value" or "--flag=value" among the arguments.
+func flagValue(args []string, flag string) string {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, flag+"="); ok {
+			return value
+		}
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// elapsed returns the duration of the entry rounded to minutes, running entries are measured until now.
+func elapsed(entry *tracker.ReportTimeEntry) string {
+	end := entry.TimeInterval.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return datetimeutils.ShortDur(end.Sub(entry.TimeInterval.Start).Round(time.Minute))
+}
+
+func hasIntervalFlags(cmd *cli.Command) bool {
+	return cmd.IsSet("date") || cmd.IsSet("start") || cmd.IsSet("end") || cmd.IsSet("at")
+}
+
+// parseLogInterval builds the start and end of a time entry from the log command flags and the optional duration.
+func parseLogInterval(cmd *cli.Command, durationArg string) (time.Time, time.Time, error) {
+	now := time.Now()
+	day, err := datetimeutils.ParseDate(cmd.String("date"), now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date: %v", err)
+	}
+
+	startClock, endClock := cmd.String("start"), cmd.String("end")
+	if at := cmd.String("at"); at != "" {
+		var ok bool
+		startClock, endClock, ok = strings.Cut(at, "-")
+		if !ok || cmd.IsSet("start") || cmd.IsSet("end") {
+			return time.Time{}, time.Time{}, errors.New("--at expects HH:MM-HH:MM and cannot be combined with --start or --end")
+		}
+	}
+
+	var start, end time.Time
+	if startClock != "" {
+		if start, err = datetimeutils.AtClock(day, startClock); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if endClock != "" {
+		if end, err = datetimeutils.AtClock(day, endClock); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	if durationArg == "" {
+		if start.IsZero() || end.IsZero() {
+			return time.Time{}, time.Time{}, errors.New("duration argument is required unless both start and end are given")
+		}
+		return start, end, nil
+	}
+
+	duration, err := time.ParseDuration(durationArg)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	switch {
+	case !start.IsZero() && !end.IsZero():
+		if end.Sub(start) != duration {
+			return time.Time{}, time.Time{}, fmt.Errorf("duration %s does not match the interval %s - %s", duration, startClock, endClock)
+		}
+	case !start.IsZero():
+		end = start.Add(duration)
+	case !end.IsZero():
+		start = end.Add(-duration)
+	default:
+		// Only the date was given, end the entry at the current time of day rounded down to 30 minutes
+		end = time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), 0, 0, now.Location()).Truncate(time.Minute * 30)
+		start = end.Add(-duration)
+	}
+
+	return start, end, nil
+}
+
+// periodFlags selects the month or the week of the report and balance commands.
+func periodFlags(weekUsage string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "month",
+			Aliases:     []string{"m"},
+			Usage:       "month, a number or YYYY-MM",
+			DefaultText: "current month",
+		},
+		&cli.IntFlag{
+			Name:        "year",
+			Aliases:     []string{"y"},
+			Usage:       "year of the month",
+			DefaultText: "current year",
+		},
+		&cli.BoolFlag{
+			Name:    "week",
+			Aliases: []string{"w"},
+			Usage:   weekUsage + ", the week of the 1st of --month when given",
+		},
+	}
+}
+
+// periodRange returns the first and the last second of the month or the week selected by the period flags.
+func periodRange(cmd *cli.Command, now time.Time) (time.Time, time.Time, error) {
+	month, err := parseReportMonth(cmd.String("month"), cmd.Int("year"), now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !cmd.Bool("week") {
+		from, to := datetimeutils.MonthRange(month)
+		return from, to, nil
+	}
+
+	day := now
+	if cmd.IsSet("month") || cmd.IsSet("year") {
+		day = month
+	}
+	from, to := datetimeutils.WeekRange(day)
+	return from, to, nil
+}
+
+// parseReportMonth returns the first day of the report month. The month is a number of the given year, or of the
+// current one when the year is 0, or YYYY-MM; the current month is used when it is empty.
+func parseReportMonth(month string, year int, now time.Time) (time.Time, error) {
+	if strings.Contains(month, "-") {
+		if year != 0 {
+			return time.Time{}, errors.New("--year cannot be combined with --month YYYY-MM")
+		}
+		first, err := time.ParseInLocation("2006-01", month, now.Location())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid month '%s', expected a number or YYYY-MM", month)
+		}
+		return first, nil
+	}
+
+	if year == 0 {
+		year = now.Year()
+	}
+	monthNumber := int(now.Month())
+	if month != "" {
+		var err error
+		monthNumber, err = strconv.Atoi(month)
+		if err != nil || monthNumber < 1 || monthNumber > 12 {
+			return time.Time{}, fmt.Errorf("invalid month '%s', expected a number or YYYY-MM", month)
+		}
+	}
+	return time.Date(year, time.Month(monthNumber), 1, 0, 0, 0, 0, now.Location()), nil
+}
@@ -3,6 +3,7 @@ module github.com/andrejsoucek/chronos
 go 1.24.2
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/joho/godotenv v1.5.1
 	github.com/jroimartin/gocui v0.5.0
 	github.com/urfave/cli/v3 v3.4.1
@@ -0,0 +1,59 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/datetimeutils"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+type balanceJSON struct {
+	From            string `json:"from"`
+	To              string `json:"to"`
+	LoggedSeconds   int64  `json:"loggedSeconds"`
+	ExpectedSeconds int64  `json:"expectedSeconds"`
+	BalanceSeconds  int64  `json:"balanceSeconds"`
+}
+
+// ShowBalance prints the time logged from from to to compared with the schedule, up to today for the current period.
+func ShowBalance(
+	ctx context.Context,
+	out io.Writer,
+	t tracker.TimeTracker,
+	schedule *tracker.Schedule,
+	from time.Time,
+	to time.Time,
+	asJSON bool,
+) error {
+	entries, err := t.GetReport(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	balance := schedule.Balance(entries, from, to, time.Now())
+
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(balanceJSON{
+			From:            balance.From.Format("2006-01-02"),
+			To:              balance.To.Format("2006-01-02"),
+			LoggedSeconds:   int64(balance.Logged.Seconds()),
+			ExpectedSeconds: int64(balance.Expected.Seconds()),
+			BalanceSeconds:  int64(balance.Difference().Seconds()),
+		})
+	}
+
+	if balance.To.Before(balance.From) {
+		fmt.Fprintf(out, "%s has not started yet\n", from.Format("2006-01-02"))
+		return nil
+	}
+	fmt.Fprintf(out, "Period:   %s - %s\n", balance.From.Format("2006-01-02"), balance.To.Format("2006-01-02"))
+	fmt.Fprintf(out, "Logged:   %s\n", datetimeutils.ShortDur(balance.Logged))
+	fmt.Fprintf(out, "Expected: %s\n", datetimeutils.ShortDur(balance.Expected))
+	fmt.Fprintf(out, "Balance:  %s\n", datetimeutils.SignedDur(balance.Difference()))
+	return nil
+}
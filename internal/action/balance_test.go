@@ -0,0 +1,59 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+// March 2026 starts on a Sunday, the first week has working days from the 2nd to the 6th
+var firstWeek = time.Date(2026, time.March, 8, 23, 59, 59, 0, time.Local)
+
+func march(day int, hour int, minute int) time.Time {
+	return time.Date(2026, time.March, day, hour, minute, 0, 0, time.Local)
+}
+
+func eightHours() *tracker.Schedule {
+	schedule := &tracker.Schedule{}
+	for day := time.Monday; day <= time.Friday; day++ {
+		schedule.Weekdays[day] = 8 * time.Hour
+	}
+	return schedule
+}
+
+func TestShowBalance(t *testing.T) {
+	fake := &fakeTracker{entries: []tracker.ReportTimeEntry{
+		entry("Development", march(2, 9, 0), march(2, 17, 0)),
+		entry("Development", march(3, 9, 0), march(3, 19, 0)),
+		entry("Development", march(4, 9, 0), march(4, 12, 0)),
+		entry("Outside", march(9, 9, 0), march(9, 17, 0)),
+	}}
+
+	var out bytes.Buffer
+	if err := ShowBalance(context.Background(), &out, fake, eightHours(), marchStart, firstWeek, false); err != nil {
+		t.Fatalf("ShowBalance() failed: %v", err)
+	}
+	for _, line := range []string{"Logged:   21h", "Expected: 40h", "Balance:  -19h"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output %q does not contain %q", out.String(), line)
+		}
+	}
+
+	out.Reset()
+	if err := ShowBalance(context.Background(), &out, fake, eightHours(), marchStart, firstWeek, true); err != nil {
+		t.Fatalf("ShowBalance() failed: %v", err)
+	}
+	var balance balanceJSON
+	if err := json.Unmarshal(out.Bytes(), &balance); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	want := balanceJSON{From: "2026-03-01", To: "2026-03-08", LoggedSeconds: 21 * 3600, ExpectedSeconds: 40 * 3600, BalanceSeconds: -19 * 3600}
+	if balance != want {
+		t.Errorf("balance = %+v, want %+v", balance, want)
+	}
+}
@@ -0,0 +1,178 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/datetimeutils"
+	"github.com/andrejsoucek/chronos/pkg/holidays"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+const (
+	issueMissing       = "missing"
+	issueUnderTarget   = "under_target"
+	issueOverTarget    = "over_target"
+	issueOverlap       = "overlap"
+	issueSpansMidnight = "spans_midnight"
+	issueNoDescription = "no_description"
+)
+
+type checkIssue struct {
+	Date    string `json:"date"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+type checkJSON struct {
+	From   string       `json:"from"`
+	To     string       `json:"to"`
+	Issues []checkIssue `json:"issues"`
+}
+
+// Check looks for working days without entries, days under or over their target, overlapping entries, entries spanning
+// midnight and entries without a description from from to to, up to today for the current period. It prints the
+// issues and returns their number. Without a schedule the working days are Monday to Friday except the days off of
+// the calendar, and the days are not compared with targets.
+func Check(
+	ctx context.Context,
+	out io.Writer,
+	t tracker.TimeTracker,
+	schedule *tracker.Schedule,
+	calendar *holidays.Calendar,
+	from time.Time,
+	to time.Time,
+	asJSON bool,
+) (int, error) {
+	entries, err := t.GetReport(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	if endOfToday := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location()).Add(-time.Nanosecond); to.After(endOfToday) {
+		to = endOfToday
+	}
+
+	issues := findIssues(entries, schedule, calendar, from, to, now)
+
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return len(issues), encoder.Encode(checkJSON{
+			From:   from.Format("2006-01-02"),
+			To:     to.Format("2006-01-02"),
+			Issues: issues,
+		})
+	}
+
+	if to.Before(from) {
+		fmt.Fprintf(out, "%s has not started yet\n", from.Format("2006-01-02"))
+		return 0, nil
+	}
+	if len(issues) == 0 {
+		fmt.Fprintf(out, "No issues from %s to %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return 0, nil
+	}
+	for _, issue := range issues {
+		date, _ := time.Parse("2006-01-02", issue.Date)
+		fmt.Fprintf(out, "%s  %s  %-14s  %s\n", issue.Date, date.Format("Mon"), issue.Kind, issue.Message)
+	}
+	fmt.Fprintf(out, "%d issue(s) found from %s to %s\n", len(issues), from.Format("2006-01-02"), to.Format("2006-01-02"))
+	return len(issues), nil
+}
+
+// findIssues checks the entries starting from from to to, sorted by the date of the issues.
+func findIssues(
+	entries []tracker.ReportTimeEntry,
+	schedule *tracker.Schedule,
+	calendar *holidays.Calendar,
+	from time.Time,
+	to time.Time,
+	now time.Time,
+) []checkIssue {
+	issues := []checkIssue{}
+	report := func(date string, kind string, format string, args ...any) {
+		issues = append(issues, checkIssue{Date: date, Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Sorted by their start for the overlaps
+	var inRange []tracker.ReportTimeEntry
+	for _, entry := range entries {
+		if !entry.TimeInterval.Start.Before(from) && !entry.TimeInterval.Start.After(to) {
+			inRange = append(inRange, entry)
+		}
+	}
+	sort.SliceStable(inRange, func(i, j int) bool {
+		return inRange[i].TimeInterval.Start.Before(inRange[j].TimeInterval.Start)
+	})
+
+	logged := map[string]time.Duration{}
+	var latest *tracker.ReportTimeEntry // Entry ending the latest so far
+	for i, entry := range inRange {
+		start := entry.TimeInterval.Start.Local()
+		end := entryEnd(entry, now)
+		date := start.Format("2006-01-02")
+		logged[date] += end.Sub(start)
+
+		if strings.TrimSpace(entry.Description) == "" {
+			report(date, issueNoDescription, "entry %s has no description", entrySpan(entry, now))
+		}
+		if end.Add(-time.Nanosecond).Format("2006-01-02") != date {
+			report(date, issueSpansMidnight, "'%s' %s spans midnight", entry.Description, entrySpan(entry, now))
+		}
+		if latest != nil && start.Before(entryEnd(*latest, now)) {
+			report(date, issueOverlap, "'%s' %s overlaps '%s' %s", entry.Description, entrySpan(entry, now), latest.Description, entrySpan(*latest, now))
+		}
+		if latest == nil || end.After(entryEnd(*latest, now)) {
+			latest = &inRange[i]
+		}
+	}
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		total := logged[date]
+		if schedule == nil {
+			weekday := day.Weekday()
+			if total == 0 && weekday != time.Saturday && weekday != time.Sunday && !calendar.IsDayOff(day) {
+				report(date, issueMissing, "no time logged")
+			}
+			continue
+		}
+
+		target := schedule.For(day)
+		switch {
+		case total == 0 && target > 0:
+			report(date, issueMissing, "no time logged, %s expected", datetimeutils.ShortDur(target))
+		case total > 0 && total < target:
+			report(date, issueUnderTarget, "logged %s of %s", datetimeutils.ShortDur(total), datetimeutils.ShortDur(target))
+		case total > 0 && target == 0:
+			report(date, issueOverTarget, "logged %s on a day without a target", datetimeutils.ShortDur(total))
+		case total > target:
+			report(date, issueOverTarget, "logged %s, %s expected", datetimeutils.ShortDur(total), datetimeutils.ShortDur(target))
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Date < issues[j].Date
+	})
+	return issues
+}
+
+// entryEnd returns the end of the entry in local time, the end of a running timer is now.
+func entryEnd(entry tracker.ReportTimeEntry, now time.Time) time.Time {
+	return entry.EndOr(now).Local()
+}
+
+func entrySpan(entry tracker.ReportTimeEntry, now time.Time) string {
+	end := "now"
+	if !entry.TimeInterval.End.IsZero() {
+		end = entryEnd(entry, now).Format("15:04")
+	}
+	return entry.TimeInterval.Start.Local().Format("15:04") + "-" + end
+}
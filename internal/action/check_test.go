@@ -0,0 +1,82 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/holidays"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+func TestCheck(t *testing.T) {
+	fake := &fakeTracker{entries: []tracker.ReportTimeEntry{
+		entry("Development", march(2, 9, 0), march(2, 17, 0)),
+		entry("Development", march(3, 9, 0), march(3, 13, 0)),
+		entry("Standup", march(3, 12, 30), march(3, 13, 30)),
+		entry("", march(3, 14, 0), march(3, 17, 0)),
+		entry("Deploy", march(4, 20, 0), march(5, 6, 0)),
+		entry("Weekend", march(7, 10, 0), march(7, 11, 0)),
+	}}
+	calendar := holidays.NewCalendar()
+	calendar.AddLeave(march(6, 0, 0), march(6, 0, 0))
+	schedule := eightHours()
+	schedule.DaysOff = calendar
+
+	var out bytes.Buffer
+	issues, err := Check(context.Background(), &out, fake, schedule, calendar, marchStart, firstWeek, true)
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+
+	var result checkJSON
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	var got []string
+	for _, issue := range result.Issues {
+		got = append(got, issue.Date+" "+issue.Kind)
+	}
+	want := []string{
+		"2026-03-03 overlap",
+		"2026-03-03 no_description",
+		"2026-03-04 spans_midnight",
+		"2026-03-04 over_target",
+		"2026-03-05 missing",
+		"2026-03-07 over_target",
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+	if issues != len(want) {
+		t.Errorf("Check() = %d, want %d", issues, len(want))
+	}
+}
+
+func TestCheckWithoutSchedule(t *testing.T) {
+	fake := &fakeTracker{entries: []tracker.ReportTimeEntry{
+		entry("Development", march(2, 9, 0), march(2, 10, 0)),
+		entry("Development", march(3, 9, 0), march(3, 10, 0)),
+		entry("Development", march(4, 9, 0), march(4, 10, 0)),
+		entry("Development", march(5, 9, 0), march(5, 10, 0)),
+	}}
+	calendar := holidays.NewCalendar()
+	calendar.AddEvents([]holidays.Event{{Date: time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC), Name: "Company Day"}})
+
+	var out bytes.Buffer
+	issues, err := Check(context.Background(), &out, fake, nil, calendar, marchStart, firstWeek, false)
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	if issues != 0 || !strings.Contains(out.String(), "No issues from 2026-03-01 to 2026-03-08") {
+		t.Errorf("Check() = %d with %q, want no issues", issues, out.String())
+	}
+
+	if _, err := Check(context.Background(), &out, &fakeTracker{err: errors.New("service unavailable")}, nil, nil, marchStart, firstWeek, false); err == nil {
+		t.Error("Check() succeeded with a failing backend")
+	}
+}
@@ -0,0 +1,318 @@
+package action
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/andrejsoucek/chronos/internal/config"
+	"github.com/andrejsoucek/chronos/pkg/apierror"
+	"github.com/andrejsoucek/chronos/pkg/clockify"
+	"github.com/andrejsoucek/chronos/pkg/gitlab"
+	"github.com/andrejsoucek/chronos/pkg/linear"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+type prompter struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+// InitConfig asks for the Clockify API key, discovers the user, workspace and project, optionally validates
+// the GitLab and Linear tokens and saves the result as a profile of the config file. The public Clockify API is used
+// unless clockifyURL is given.
+func InitConfig(ctx context.Context, in io.Reader, out io.Writer, path string, clockifyURL string, httpClient *http.Client) error {
+	cfg, err := config.Load(path)
+	if errors.Is(err, config.ErrNotFound) {
+		cfg = &config.Config{}
+	} else if err != nil {
+		return err
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*config.Profile{}
+	}
+
+	p := &prompter{scanner: bufio.NewScanner(in), out: out}
+
+	name, err := p.ask("Profile name", "work")
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Profiles[name]; ok {
+		overwrite, err := p.confirm(fmt.Sprintf("Profile '%s' already exists, overwrite it?", name))
+		if err != nil {
+			return err
+		}
+		if !overwrite {
+			return errors.New("config init cancelled")
+		}
+	}
+
+	profile := &config.Profile{Backend: "clockify"}
+	if err := p.initClockify(ctx, profile, clockifyURL, httpClient); err != nil {
+		return err
+	}
+	if err := p.initGitlab(ctx, profile, httpClient); err != nil {
+		return err
+	}
+	if err := p.initLinear(ctx, profile, httpClient); err != nil {
+		return err
+	}
+
+	cfg.Profiles[name] = profile
+	if cfg.DefaultProfile == "" {
+		cfg.DefaultProfile = name
+	} else if cfg.DefaultProfile != name {
+		makeDefault, err := p.confirm(fmt.Sprintf("Use '%s' as the default profile instead of '%s'?", name, cfg.DefaultProfile))
+		if err != nil {
+			return err
+		}
+		if makeDefault {
+			cfg.DefaultProfile = name
+		}
+	}
+
+	if err := config.Save(path, cfg); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Configuration saved to %s\n", path)
+	return nil
+}
+
+// ShowConfig prints the profile in the config file format with the API keys hidden.
+func ShowConfig(out io.Writer, name string, profile *config.Profile) error {
+	if name != "" {
+		fmt.Fprintf(out, "# Profile %s\n", name)
+	}
+	return toml.NewEncoder(out).Encode(profile.Redacted())
+}
+
+// ValidateConfig checks the profile and tries the credentials of the backend and the configured integrations.
+func ValidateConfig(ctx context.Context, out io.Writer, profile *config.Profile, t tracker.TimeTracker, integrations Integrations) error {
+	var errs []error
+	check := func(name string, err error) {
+		if err != nil {
+			fmt.Fprintf(out, "✗ %s: %v\n", name, err)
+			errs = append(errs, err)
+			return
+		}
+		fmt.Fprintf(out, "✓ %s\n", name)
+	}
+
+	check("configuration", profile.Validate())
+	_, err := t.GetWorkspaceID(ctx)
+	check(profile.Backend+" credentials", err)
+
+	if integrations.GitlabErr != nil {
+		check("gitlab credentials", integrations.GitlabErr)
+	} else if integrations.Gitlab.IsConfigured() {
+		_, err := integrations.Gitlab.GetCurrentUser(ctx)
+		check("gitlab credentials", err)
+	}
+	if integrations.LinearErr != nil {
+		check("linear credentials", integrations.LinearErr)
+	} else if integrations.Linear.IsConfigured() {
+		_, err := integrations.Linear.GetViewer(ctx)
+		check("linear credentials", err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("configuration is not valid, %d check(s) failed", len(errs))
+	}
+	return nil
+}
+
+func (p *prompter) initClockify(ctx context.Context, profile *config.Profile, clockifyURL string, httpClient *http.Client) error {
+	if clockifyURL != "" {
+		profile.Clockify.UserURL = clockifyURL + "user"
+	}
+
+	// Only the values entered by the user are saved, the default API URLs are resolved separately
+	resolved := *profile
+	resolved.ApplyDefaults()
+	c := clockify.NewClockify(&clockify.ClockifyConfig{
+		UserURL:    resolved.Clockify.UserURL,
+		HTTPClient: httpClient,
+	})
+
+	var user *clockify.User
+	for user == nil {
+		apiKey, err := p.ask("Clockify API key (https://clockify.me/user/settings)", "")
+		if err != nil {
+			return err
+		}
+		c.Config.APIKey = apiKey
+		user, err = c.GetUser(ctx)
+		if apierror.IsUnauthorized(err) {
+			fmt.Fprintln(p.out, "The API key was rejected, try again.")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		profile.Clockify.APIKey = apiKey
+		profile.Clockify.UserID = user.ID
+	}
+	fmt.Fprintf(p.out, "Signed in as %s <%s>\n", user.Name, user.Email)
+
+	workspaces, err := c.GetWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+	if len(workspaces) == 0 {
+		return errors.New("the Clockify account has no workspace")
+	}
+	names := make([]string, 0, len(workspaces))
+	current := 0
+	for i, workspace := range workspaces {
+		names = append(names, workspace.Name)
+		if workspace.ID == user.ActiveWorkspace {
+			current = i
+		}
+	}
+	i, err := p.choose("Workspace", names, current)
+	if err != nil {
+		return err
+	}
+	profile.Clockify.WorkspaceID = workspaces[i].ID
+	if clockifyURL != "" {
+		profile.Clockify.BaseURL = clockifyURL + "workspaces/" + workspaces[i].ID + "/"
+	}
+
+	resolved = *profile
+	resolved.ApplyDefaults()
+	c.Config.WorkspaceID = resolved.Clockify.WorkspaceID
+	c.Config.BaseURL = resolved.Clockify.BaseURL
+
+	projects, err := c.GetProjects(ctx)
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		return errors.New("the Clockify workspace has no active project")
+	}
+	names = make([]string, 0, len(projects))
+	for _, project := range projects {
+		if project.ClientName != "" {
+			names = append(names, project.Name+" ("+project.ClientName+")")
+			continue
+		}
+		names = append(names, project.Name)
+	}
+	i, err = p.choose("Default project", names, 0)
+	if err != nil {
+		return err
+	}
+	profile.DefaultProject = projects[i].ID
+
+	return nil
+}
+
+func (p *prompter) initGitlab(ctx context.Context, profile *config.Profile, httpClient *http.Client) error {
+	resolved := *profile
+	resolved.ApplyDefaults()
+
+	for {
+		token, err := p.ask("GitLab access token (empty to skip)", "")
+		if err != nil || token == "" {
+			return err
+		}
+		baseURL, err := p.ask("GitLab API URL", resolved.Gitlab.BaseURL)
+		if err != nil {
+			return err
+		}
+
+		g := gitlab.NewGitlab(&gitlab.GitlabConfig{APIKey: token, BaseURL: baseURL, HTTPClient: httpClient})
+		user, err := g.GetCurrentUser(ctx)
+		if err != nil {
+			fmt.Fprintf(p.out, "GitLab token could not be validated: %v\n", err)
+			continue
+		}
+		fmt.Fprintf(p.out, "GitLab user %s (ID %d)\n", user.Username, user.ID)
+
+		profile.Gitlab.AccessToken = token
+		profile.Gitlab.UserID = strconv.FormatInt(user.ID, 10)
+		if baseURL != resolved.Gitlab.BaseURL {
+			profile.Gitlab.BaseURL = baseURL
+		}
+		return nil
+	}
+}
+
+func (p *prompter) initLinear(ctx context.Context, profile *config.Profile, httpClient *http.Client) error {
+	resolved := *profile
+	resolved.ApplyDefaults()
+
+	for {
+		apiKey, err := p.ask("Linear API key (empty to skip)", "")
+		if err != nil || apiKey == "" {
+			return err
+		}
+
+		l := linear.NewLinear(&linear.LinearConfig{APIKey: apiKey, BaseURL: resolved.Linear.BaseURL, HTTPClient: httpClient})
+		viewer, err := l.GetViewer(ctx)
+		if err != nil {
+			fmt.Fprintf(p.out, "Linear API key could not be validated: %v\n", err)
+			continue
+		}
+		fmt.Fprintf(p.out, "Linear user %s\n", viewer.Name)
+
+		profile.Linear.APIKey = apiKey
+		return nil
+	}
+}
+
+// ask reads a line of input, the default is used when the line is empty.
+func (p *prompter) ask(question string, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	if !p.scanner.Scan() {
+		if err := p.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.ErrUnexpectedEOF
+	}
+
+	answer := strings.TrimSpace(p.scanner.Text())
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+func (p *prompter) confirm(question string) (bool, error) {
+	answer, err := p.ask(question+" [y/N]", "")
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes"), nil
+}
+
+// choose lists the options and returns the index of the selected one.
+func (p *prompter) choose(question string, options []string, defaultIndex int) (int, error) {
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+	}
+
+	for {
+		answer, err := p.ask(question, strconv.Itoa(defaultIndex+1))
+		if err != nil {
+			return 0, err
+		}
+		i, err := strconv.Atoi(answer)
+		if err == nil && i >= 1 && i <= len(options) {
+			return i - 1, nil
+		}
+		fmt.Fprintf(p.out, "Enter a number between 1 and %d.\n", len(options))
+	}
+}
@@ -0,0 +1,131 @@
+package action
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+// fakeTracker is an in-memory time tracking backend supporting timers.
+type fakeTracker struct {
+	entries []tracker.ReportTimeEntry
+	logged  []*tracker.TimeEntry // Entries passed to LogTime and StartTimer
+	err     error                // Returned by every call when set
+	nextID  int
+}
+
+// withoutTimers hides the timer methods of the fake, like a backend without timers.
+type withoutTimers struct {
+	tracker.TimeTracker
+}
+
+func (f *fakeTracker) GetWorkspaceID(ctx context.Context) (string, error) {
+	return "workspace", f.err
+}
+
+func (f *fakeTracker) LogTime(ctx context.Context, te *tracker.TimeEntry) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	start, end := te.Interval()
+	return f.add(te, start, end), nil
+}
+
+func (f *fakeTracker) EditLog(ctx context.Context, ID string, te *tracker.TimeEntry) error {
+	if f.err != nil {
+		return f.err
+	}
+	for i := range f.entries {
+		if f.entries[i].ID == ID {
+			f.entries[i].Description = te.Description
+			f.entries[i].TimeInterval.Start, f.entries[i].TimeInterval.End = te.Interval()
+		}
+	}
+	return nil
+}
+
+func (f *fakeTracker) DeleteLog(ctx context.Context, ID string) error {
+	if f.err != nil {
+		return f.err
+	}
+	for i := range f.entries {
+		if f.entries[i].ID == ID {
+			f.entries = append(f.entries[:i], f.entries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (f *fakeTracker) GetReport(ctx context.Context, from time.Time, to time.Time) ([]tracker.ReportTimeEntry, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var entries []tracker.ReportTimeEntry
+	for _, entry := range f.entries {
+		if !entry.TimeInterval.Start.Before(from) && !entry.TimeInterval.Start.After(to) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (f *fakeTracker) StartTimer(ctx context.Context, te *tracker.TimeEntry) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return f.add(te, te.Time, time.Time{}), nil
+}
+
+func (f *fakeTracker) StopTimer(ctx context.Context, end time.Time) (*tracker.ReportTimeEntry, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for i := range f.entries {
+		if f.entries[i].TimeInterval.End.IsZero() {
+			f.entries[i].TimeInterval.End = end
+			stopped := f.entries[i]
+			return &stopped, nil
+		}
+	}
+	return nil, tracker.ErrNoRunningTimer
+}
+
+func (f *fakeTracker) GetRunningTimer(ctx context.Context) (*tracker.ReportTimeEntry, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, entry := range f.entries {
+		if entry.TimeInterval.End.IsZero() {
+			return &entry, nil
+		}
+	}
+	return nil, tracker.ErrNoRunningTimer
+}
+
+func (f *fakeTracker) add(te *tracker.TimeEntry, start time.Time, end time.Time) string {
+	f.nextID++
+	f.logged = append(f.logged, te)
+
+	entry := tracker.ReportTimeEntry{
+		ID:          strconv.Itoa(f.nextID),
+		Description: te.Description,
+		ProjectID:   te.ProjectID,
+		TaskID:      te.TaskID,
+		TagIDs:      te.TagIDs,
+	}
+	entry.TimeInterval.Start, entry.TimeInterval.End = start, end
+	f.entries = append(f.entries, entry)
+	return entry.ID
+}
+
+// entry creates a logged entry from start to end, a zero end makes it a running timer.
+func entry(description string, start time.Time, end time.Time) tracker.ReportTimeEntry {
+	var e tracker.ReportTimeEntry
+	e.Description = description
+	e.ProjectID = "p1"
+	e.TimeInterval.Start, e.TimeInterval.End = start, end
+	return e
+}
@@ -1,9 +1,11 @@
 package action
 
 import (
-	"github.com/andrejsoucek/chronos/pkg/clockify"
+	"context"
+
+	"github.com/andrejsoucek/chronos/pkg/tracker"
 )
 
-func GetWorkspaceID(clockify *clockify.Clockify) (string, error) {
-	return clockify.GetWorkspaceID()
+func GetWorkspaceID(ctx context.Context, t tracker.TimeTracker) (string, error) {
+	return t.GetWorkspaceID(ctx)
 }
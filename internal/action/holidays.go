@@ -0,0 +1,44 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/holidays"
+)
+
+type holidayJSON struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// ShowHolidays prints the public holidays and the leave from from to to.
+func ShowHolidays(out io.Writer, calendar *holidays.Calendar, from time.Time, to time.Time, asJSON bool) error {
+	days := calendar.Between(from, to)
+
+	if asJSON {
+		list := make([]holidayJSON, 0, len(days))
+		for _, day := range days {
+			list = append(list, holidayJSON{
+				Date: day.Date.Format("2006-01-02"),
+				Name: day.Name,
+				Kind: string(day.Kind),
+			})
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	}
+
+	if len(days) == 0 {
+		fmt.Fprintf(out, "No holidays or leave from %s to %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return nil
+	}
+	for _, day := range days {
+		fmt.Fprintf(out, "%s  %s  %-7s  %s\n", day.Date.Format("2006-01-02"), day.Date.Format("Mon"), day.Kind, day.Name)
+	}
+	return nil
+}
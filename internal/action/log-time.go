@@ -1,18 +1,92 @@
 package action
 
 import (
+	"context"
+	"fmt"
 	"time"
 
-	"github.com/andrejsoucek/chronos/pkg/clockify"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
 )
 
-func LogTime(c *clockify.Clockify, projectId string, duration time.Duration, taskName string) error {
-	te := &clockify.TimeEntry{
+// EntryDetails are the optional attributes of new time entries.
+type EntryDetails struct {
+	TaskID   string
+	TagIDs   []string
+	Billable *bool             // Nil uses the default of the backend
+	Rounding *tracker.Rounding // Nil keeps the time as given
+}
+
+func (d EntryDetails) apply(te *tracker.TimeEntry) {
+	te.TaskID = d.TaskID
+	te.TagIDs = d.TagIDs
+	te.Billable = d.Billable
+	te.Rounding = d.Rounding
+}
+
+// LogTime logs an entry ending now and returns its interval after rounding.
+func LogTime(
+	ctx context.Context,
+	t tracker.TimeTracker,
+	projectId string,
+	duration time.Duration,
+	taskName string,
+	details EntryDetails,
+) (time.Time, time.Time, error) {
+	te := &tracker.TimeEntry{
 		Time:        time.Now(),
 		Duration:    duration,
 		Description: taskName,
 		ProjectID:   projectId,
 	}
-	_, err := c.LogTime(te)
-	return err
+	details.apply(te)
+	start, end := te.Interval()
+	_, err := t.LogTime(ctx, te)
+	return start, end, err
+}
+
+// LogTimeInterval logs an entry with exact start and end, refusing to overlap already logged entries. The interval
+// is rounded first when rounding is configured, the rounded one is returned.
+func LogTimeInterval(
+	ctx context.Context,
+	t tracker.TimeTracker,
+	projectId string,
+	start time.Time,
+	end time.Time,
+	taskName string,
+	details EntryDetails,
+) (time.Time, time.Time, error) {
+	if !end.After(start) {
+		return start, end, fmt.Errorf("end time %s must be after start time %s", end.Format("2006-01-02 15:04"), start.Format("2006-01-02 15:04"))
+	}
+
+	te := &tracker.TimeEntry{
+		Time:        end,
+		Duration:    end.Sub(start),
+		Description: taskName,
+		ProjectID:   projectId,
+		Exact:       true,
+	}
+	details.apply(te)
+	start, end = te.Interval()
+
+	// Fetch a wider range so that entries starting on the previous day are taken into account as well
+	existing, err := t.GetReport(ctx, start.AddDate(0, 0, -1), end.AddDate(0, 0, 1))
+	if err != nil {
+		return start, end, err
+	}
+
+	for _, entry := range existing {
+		entryEnd := entry.EndOr(time.Now())
+		if entry.TimeInterval.Start.Before(end) && start.Before(entryEnd) {
+			return start, end, fmt.Errorf(
+				"time entry overlaps with existing entry '%s' (%s - %s)",
+				entry.Description,
+				entry.TimeInterval.Start.Local().Format("2006-01-02 15:04"),
+				entryEnd.Local().Format("15:04"),
+			)
+		}
+	}
+
+	_, err = t.LogTime(ctx, te)
+	return start, end, err
 }
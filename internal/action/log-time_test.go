@@ -0,0 +1,146 @@
+package action
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+func TestLogTime(t *testing.T) {
+	fake := &fakeTracker{}
+	billable := true
+	details := EntryDetails{TaskID: "task", TagIDs: []string{"tag"}, Billable: &billable}
+
+	start, end, err := LogTime(context.Background(), fake, "p1", 90*time.Minute, "Code review", details)
+	if err != nil {
+		t.Fatalf("LogTime() failed: %v", err)
+	}
+	if end.Sub(start) != 90*time.Minute {
+		t.Errorf("logged %s, want 1h30m", end.Sub(start))
+	}
+	// Without rounding the end is the current time rounded down to 30 minutes
+	if end.Truncate(30*time.Minute) != end || end.After(time.Now()) {
+		t.Errorf("end %s is not now rounded down to 30 minutes", end)
+	}
+
+	if len(fake.logged) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(fake.logged))
+	}
+	logged := fake.logged[0]
+	if logged.Description != "Code review" || logged.ProjectID != "p1" || logged.TaskID != "task" ||
+		len(logged.TagIDs) != 1 || logged.Billable == nil || !*logged.Billable {
+		t.Errorf("unexpected entry %+v", logged)
+	}
+}
+
+func TestLogTimeRounding(t *testing.T) {
+	fake := &fakeTracker{}
+	details := EntryDetails{Rounding: &tracker.Rounding{Granularity: 15 * time.Minute, Mode: tracker.RoundUp, Target: tracker.RoundDuration}}
+
+	start, end, err := LogTime(context.Background(), fake, "p1", 20*time.Minute, "Standup", details)
+	if err != nil {
+		t.Fatalf("LogTime() failed: %v", err)
+	}
+	if end.Sub(start) != 30*time.Minute {
+		t.Errorf("logged %s, want 30m after rounding", end.Sub(start))
+	}
+}
+
+func TestLogTimeInterval(t *testing.T) {
+	day := time.Date(2026, time.March, 10, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	tests := []struct {
+		name      string
+		existing  []tracker.ReportTimeEntry
+		start     time.Time
+		end       time.Time
+		rounding  *tracker.Rounding
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   string
+	}{
+		{
+			name:      "exact interval",
+			existing:  []tracker.ReportTimeEntry{entry("Standup", at(9, 0), at(9, 15))},
+			start:     at(9, 15),
+			end:       at(10, 7),
+			wantStart: at(9, 15),
+			wantEnd:   at(10, 7),
+		},
+		{
+			name:    "end before start",
+			start:   at(10, 0),
+			end:     at(9, 0),
+			wantErr: "must be after start time",
+		},
+		{
+			name:     "overlap",
+			existing: []tracker.ReportTimeEntry{entry("Standup", at(9, 0), at(9, 15))},
+			start:    at(9, 10),
+			end:      at(10, 0),
+			wantErr:  "overlaps with existing entry 'Standup'",
+		},
+		{
+			name:     "overlap with the previous day",
+			existing: []tracker.ReportTimeEntry{entry("Deploy", at(-2, 0), at(1, 0))},
+			start:    at(0, 30),
+			end:      at(2, 0),
+			wantErr:  "overlaps with existing entry 'Deploy'",
+		},
+		{
+			name:     "overlap with a running timer",
+			existing: []tracker.ReportTimeEntry{entry("Running", time.Now().Add(-time.Hour), time.Time{})},
+			start:    time.Now().Add(-30 * time.Minute),
+			end:      time.Now().Add(-10 * time.Minute),
+			wantErr:  "overlaps with existing entry 'Running'",
+		},
+		{
+			name:     "overlap after rounding",
+			existing: []tracker.ReportTimeEntry{entry("Standup", at(9, 0), at(9, 15))},
+			start:    at(8, 45),
+			end:      at(8, 50),
+			rounding: &tracker.Rounding{Granularity: 30 * time.Minute, Mode: tracker.RoundUp, Target: tracker.RoundDuration},
+			wantErr:  "overlaps with existing entry 'Standup'",
+		},
+		{
+			name:      "rounded interval",
+			start:     at(9, 8),
+			end:       at(9, 53),
+			rounding:  &tracker.Rounding{Granularity: 15 * time.Minute, Mode: tracker.RoundNearest, Target: tracker.RoundEnd},
+			wantStart: at(9, 15),
+			wantEnd:   at(10, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTracker{entries: tt.existing}
+			start, end, err := LogTimeInterval(context.Background(), fake, "p1", tt.start, tt.end, "Work", EntryDetails{Rounding: tt.rounding})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LogTimeInterval() = %v, want an error about %s", err, tt.wantErr)
+				}
+				if len(fake.logged) != 0 {
+					t.Error("the entry was logged despite the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LogTimeInterval() failed: %v", err)
+			}
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("LogTimeInterval() = %s - %s, want %s - %s", start, end, tt.wantStart, tt.wantEnd)
+			}
+			logged := fake.entries[len(fake.entries)-1]
+			if !logged.TimeInterval.Start.Equal(tt.wantStart) || !logged.TimeInterval.End.Equal(tt.wantEnd) {
+				t.Errorf("logged %s - %s, want %s - %s", logged.TimeInterval.Start, logged.TimeInterval.End, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
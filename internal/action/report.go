@@ -1,30 +1,99 @@
 package action
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/andrejsoucek/chronos/internal/ui"
-	"github.com/andrejsoucek/chronos/pkg/clockify"
 	"github.com/andrejsoucek/chronos/pkg/gitlab"
 	"github.com/andrejsoucek/chronos/pkg/linear"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
 )
 
-func ShowReport(c *clockify.Clockify, l *linear.Linear, g *gitlab.Gitlab, projectId string, from time.Time, to time.Time) error {
-	data, err := c.GetReport(from, to)
-	if err != nil {
-		return err
-	}
+const reportLoadTimeout = 30 * time.Second
 
-	linearLastActivity, err := l.GetLastActivity(from, to)
-	if err != nil {
-		return err
-	}
+// Integrations are the activity sources of the report. An error reading the secret of one is shown in place of its
+// activity, it does not stop the report.
+type Integrations struct {
+	Linear    *linear.Linear
+	LinearErr error
+	Gitlab    *gitlab.Gitlab
+	GitlabErr error
+}
+
+// ShowReport loads the time entries, the projects and the recent activity concurrently and renders the report. Only
+// a failure to load the time entries is fatal, the other errors are shown in the report instead.
+func ShowReport(
+	ctx context.Context,
+	t tracker.TimeTracker,
+	integrations Integrations,
+	projectId string,
+	config ui.ReportConfig,
+	from time.Time,
+	to time.Time,
+) error {
+	l, g := integrations.Linear, integrations.Gitlab
+	loadCtx, cancel := context.WithTimeout(ctx, reportLoadTimeout)
+	defer cancel()
+
+	var (
+		wg                 sync.WaitGroup
+		data               []tracker.ReportTimeEntry
+		dataErr            error
+		linearLastActivity []linear.LastActivityItem
+		linearErr          error
+		gitlabLastActivity []gitlab.LastActivityItem
+		gitlabErr          error
+		projects           []tracker.Project
+		projectsErr        error
+	)
+
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		data, dataErr = t.GetReport(loadCtx, from, to)
+	}()
+	go func() {
+		defer wg.Done()
+		if integrations.LinearErr != nil {
+			linearErr = integrations.LinearErr
+			return
+		}
+		if !l.IsConfigured() {
+			linearErr = errors.New("linear is not configured")
+			return
+		}
+		linearLastActivity, linearErr = l.GetLastActivity(loadCtx, from, to)
+	}()
+	go func() {
+		defer wg.Done()
+		if integrations.GitlabErr != nil {
+			gitlabErr = integrations.GitlabErr
+			return
+		}
+		if !g.IsConfigured() {
+			gitlabErr = errors.New("gitlab is not configured")
+			return
+		}
+		gitlabLastActivity, gitlabErr = g.GetLastActivity(loadCtx, from, to)
+	}()
+	go func() {
+		defer wg.Done()
+		lister, ok := t.(tracker.ProjectLister)
+		if !ok {
+			projectsErr = errors.New("the time tracker cannot list projects")
+			return
+		}
+		projects, projectsErr = lister.ListProjects(loadCtx)
+	}()
+	wg.Wait()
 
-	gitlabLastActivity, err := g.GetLastActivity(from, to)
-	if err != nil {
-		return err
+	if dataErr != nil {
+		return dataErr
 	}
 
-	ui.RenderReport(c, projectId, from.Month(), data, linearLastActivity, gitlabLastActivity)
+	ui.RenderReport(ctx, t, l, g, projectId, config, projects, projectsErr, from, data, linearLastActivity, linearErr, gitlabLastActivity, gitlabErr)
 	return nil
 }
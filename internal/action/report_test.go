@@ -0,0 +1,28 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andrejsoucek/chronos/internal/ui"
+	"github.com/andrejsoucek/chronos/pkg/gitlab"
+	"github.com/andrejsoucek/chronos/pkg/linear"
+)
+
+var (
+	marchStart = time.Date(2026, time.March, 1, 0, 0, 0, 0, time.Local)
+	marchEnd   = time.Date(2026, time.March, 31, 23, 59, 59, 0, time.Local)
+)
+
+func TestShowReportFailsWithoutEntries(t *testing.T) {
+	fake := &fakeTracker{err: errors.New("service unavailable")}
+	l := linear.NewLinear(&linear.LinearConfig{})
+	g := gitlab.NewGitlab(&gitlab.GitlabConfig{})
+
+	err := ShowReport(context.Background(), fake, Integrations{Linear: l, Gitlab: g}, "p1", ui.ReportConfig{}, marchStart, marchEnd)
+	if err == nil || err.Error() != "service unavailable" {
+		t.Fatalf("ShowReport() = %v, want the error of the backend", err)
+	}
+}
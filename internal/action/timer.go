@@ -0,0 +1,97 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+func StartTimer(ctx context.Context, t tracker.TimeTracker, projectId string, taskName string, details EntryDetails) error {
+	timer, err := asTimer(t)
+	if err != nil {
+		return err
+	}
+
+	running, err := timer.GetRunningTimer(ctx)
+	if err == nil {
+		return fmt.Errorf("timer is already running for '%s', use switch to start another task", running.Description)
+	}
+	if !errors.Is(err, tracker.ErrNoRunningTimer) {
+		return err
+	}
+
+	te := &tracker.TimeEntry{
+		Time:        time.Now(),
+		Description: taskName,
+		ProjectID:   projectId,
+	}
+	details.apply(te)
+	_, err = timer.StartTimer(ctx, te)
+	return err
+}
+
+func StopTimer(ctx context.Context, t tracker.TimeTracker) (*tracker.ReportTimeEntry, error) {
+	timer, err := asTimer(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return timer.StopTimer(ctx, time.Now())
+}
+
+func GetTimerStatus(ctx context.Context, t tracker.TimeTracker) (*tracker.ReportTimeEntry, error) {
+	timer, err := asTimer(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return timer.GetRunningTimer(ctx)
+}
+
+// SwitchTimer stops the running timer and starts a new one at the very same moment, so there is no gap between them.
+// The stopped entry is nil when no timer was running.
+func SwitchTimer(
+	ctx context.Context,
+	t tracker.TimeTracker,
+	projectId string,
+	taskName string,
+	details EntryDetails,
+) (*tracker.ReportTimeEntry, error) {
+	timer, err := asTimer(t)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	stopped, err := timer.StopTimer(ctx, now)
+	if err != nil && !errors.Is(err, tracker.ErrNoRunningTimer) {
+		return nil, err
+	}
+
+	te := &tracker.TimeEntry{
+		Time:        now,
+		Description: taskName,
+		ProjectID:   projectId,
+	}
+	details.apply(te)
+	if _, err := timer.StartTimer(ctx, te); err != nil {
+		if stopped != nil {
+			return stopped, fmt.Errorf("stopped '%s' but failed to start the new timer: %v", stopped.Description, err)
+		}
+		return nil, err
+	}
+
+	return stopped, nil
+}
+
+func asTimer(t tracker.TimeTracker) (tracker.Timer, error) {
+	timer, ok := t.(tracker.Timer)
+	if !ok {
+		return nil, errors.New("the configured time tracking backend does not support timers")
+	}
+	return timer, nil
+}
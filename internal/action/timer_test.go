@@ -0,0 +1,96 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+func TestStartAndStopTimer(t *testing.T) {
+	fake := &fakeTracker{}
+	ctx := context.Background()
+
+	if _, err := GetTimerStatus(ctx, fake); !errors.Is(err, tracker.ErrNoRunningTimer) {
+		t.Fatalf("GetTimerStatus() = %v, want ErrNoRunningTimer", err)
+	}
+
+	if err := StartTimer(ctx, fake, "p1", "Deploy", EntryDetails{TaskID: "task"}); err != nil {
+		t.Fatalf("StartTimer() failed: %v", err)
+	}
+	running, err := GetTimerStatus(ctx, fake)
+	if err != nil {
+		t.Fatalf("GetTimerStatus() failed: %v", err)
+	}
+	if running.Description != "Deploy" || running.ProjectID != "p1" || running.TaskID != "task" {
+		t.Errorf("unexpected running timer %+v", running)
+	}
+
+	err = StartTimer(ctx, fake, "p1", "Review", EntryDetails{})
+	if err == nil || !strings.Contains(err.Error(), "already running for 'Deploy'") {
+		t.Fatalf("StartTimer() = %v, want an error about the running timer", err)
+	}
+
+	stopped, err := StopTimer(ctx, fake)
+	if err != nil {
+		t.Fatalf("StopTimer() failed: %v", err)
+	}
+	if stopped.Description != "Deploy" || stopped.TimeInterval.End.IsZero() {
+		t.Errorf("unexpected stopped entry %+v", stopped)
+	}
+	if _, err := StopTimer(ctx, fake); !errors.Is(err, tracker.ErrNoRunningTimer) {
+		t.Errorf("StopTimer() = %v, want ErrNoRunningTimer", err)
+	}
+}
+
+func TestSwitchTimer(t *testing.T) {
+	fake := &fakeTracker{entries: []tracker.ReportTimeEntry{entry("Deploy", time.Now().Add(-time.Hour), time.Time{})}}
+	ctx := context.Background()
+
+	stopped, err := SwitchTimer(ctx, fake, "p1", "Review", EntryDetails{})
+	if err != nil {
+		t.Fatalf("SwitchTimer() failed: %v", err)
+	}
+	if stopped == nil || stopped.Description != "Deploy" {
+		t.Fatalf("SwitchTimer() stopped %+v, want Deploy", stopped)
+	}
+
+	running, err := GetTimerStatus(ctx, fake)
+	if err != nil {
+		t.Fatalf("GetTimerStatus() failed: %v", err)
+	}
+	if running.Description != "Review" || !running.TimeInterval.Start.Equal(stopped.TimeInterval.End) {
+		t.Errorf("new timer %s starts at %s, want Review starting at %s",
+			running.Description, running.TimeInterval.Start, stopped.TimeInterval.End)
+	}
+
+	// Without a running timer the new one is started only
+	fake = &fakeTracker{}
+	stopped, err = SwitchTimer(ctx, fake, "p1", "Review", EntryDetails{})
+	if err != nil || stopped != nil {
+		t.Fatalf("SwitchTimer() = %+v, %v, want only a new timer", stopped, err)
+	}
+	if len(fake.entries) != 1 {
+		t.Errorf("%d entries, want the new timer", len(fake.entries))
+	}
+}
+
+func TestTimerErrors(t *testing.T) {
+	ctx := context.Background()
+
+	backend := withoutTimers{&fakeTracker{}}
+	if err := StartTimer(ctx, backend, "p1", "Deploy", EntryDetails{}); err == nil || !strings.Contains(err.Error(), "does not support timers") {
+		t.Errorf("StartTimer() = %v, want an error about timers", err)
+	}
+
+	failing := &fakeTracker{err: errors.New("service unavailable")}
+	if err := StartTimer(ctx, failing, "p1", "Deploy", EntryDetails{}); err == nil || err.Error() != "service unavailable" {
+		t.Errorf("StartTimer() = %v, want the error of the backend", err)
+	}
+	if _, err := SwitchTimer(ctx, failing, "p1", "Deploy", EntryDetails{}); err == nil {
+		t.Error("SwitchTimer() succeeded with a failing backend")
+	}
+}
@@ -0,0 +1,353 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/clockify"
+)
+
+// Cached names are refreshed after this time or when a name cannot be found
+const cacheTTL = 24 * time.Hour
+
+// noMatchError is returned when nothing matches the query, the lists are fetched again before giving up.
+type noMatchError struct {
+	kind  string
+	query string
+}
+
+func (e *noMatchError) Error() string {
+	return fmt.Sprintf("no %s matches '%s'", e.kind, e.query)
+}
+
+// Source lists the projects, tasks and tags of the workspace, it is implemented by the Clockify client.
+type Source interface {
+	GetProjects(ctx context.Context) ([]clockify.Project, error)
+	GetTasks(ctx context.Context, projectID string) ([]clockify.Task, error)
+	GetTags(ctx context.Context) ([]clockify.Tag, error)
+}
+
+// Catalog resolves human-readable project, task and tag names to their IDs, keeping the lists in a local cache.
+type Catalog struct {
+	source    Source
+	cachePath string
+	cache     *cache
+	refreshed bool // The cache was fetched during this run, so refreshing it again does not help
+}
+
+type cache struct {
+	UpdatedAt time.Time                  `json:"updatedAt"`
+	Projects  []clockify.Project         `json:"projects"`
+	Tasks     map[string][]clockify.Task `json:"tasks"` // By project ID
+	Tags      []clockify.Tag             `json:"tags"`
+}
+
+// DefaultCachePath returns the cache file of the workspace in the user cache directory.
+func DefaultCachePath(workspaceID string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "chronos", "clockify-"+workspaceID+".json"), nil
+}
+
+func New(source Source, cachePath string) *Catalog {
+	return &Catalog{
+		source:    source,
+		cachePath: cachePath,
+	}
+}
+
+// Project returns the project matching the name or ID.
+func (c *Catalog) Project(ctx context.Context, query string) (*clockify.Project, error) {
+	for {
+		cache, err := c.load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		i, err := match("project", query, projectNames(cache.Projects), projectIDs(cache.Projects))
+		if isNoMatch(err) && c.retry() {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &cache.Projects[i], nil
+	}
+}
+
+// Task returns the task of the project matching the name or ID.
+func (c *Catalog) Task(ctx context.Context, projectID string, query string) (*clockify.Task, error) {
+	for {
+		tasks, err := c.tasks(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(tasks))
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			names = append(names, task.Name)
+			ids = append(ids, task.ID)
+		}
+
+		i, err := match("task", query, names, ids)
+		if isNoMatch(err) && c.retry() {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &tasks[i], nil
+	}
+}
+
+// Tag returns the tag matching the name or ID.
+func (c *Catalog) Tag(ctx context.Context, query string) (*clockify.Tag, error) {
+	for {
+		cache, err := c.load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(cache.Tags))
+		ids := make([]string, 0, len(cache.Tags))
+		for _, tag := range cache.Tags {
+			names = append(names, tag.Name)
+			ids = append(ids, tag.ID)
+		}
+
+		i, err := match("tag", query, names, ids)
+		if isNoMatch(err) && c.retry() {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &cache.Tags[i], nil
+	}
+}
+
+func (c *Catalog) ProjectNames(ctx context.Context) ([]string, error) {
+	cache, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return projectNames(cache.Projects), nil
+}
+
+func (c *Catalog) TaskNames(ctx context.Context, projectID string) ([]string, error) {
+	tasks, err := c.tasks(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	return names, nil
+}
+
+func (c *Catalog) TagNames(ctx context.Context) ([]string, error) {
+	cache, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(cache.Tags))
+	for _, tag := range cache.Tags {
+		names = append(names, tag.Name)
+	}
+	return names, nil
+}
+
+func isNoMatch(err error) bool {
+	var noMatch *noMatchError
+	return errors.As(err, &noMatch)
+}
+
+// retry drops the cache so that the next lookup fetches fresh lists, it reports false when they are fresh already.
+func (c *Catalog) retry() bool {
+	if c.refreshed {
+		return false
+	}
+	c.cache = nil
+	c.refreshed = true
+	return true
+}
+
+// load returns the cached lists of projects and tags, fetching them when the cache is missing or expired.
+func (c *Catalog) load(ctx context.Context) (*cache, error) {
+	if c.cache != nil {
+		return c.cache, nil
+	}
+
+	if !c.refreshed {
+		if cached, err := c.readCache(); err == nil && time.Since(cached.UpdatedAt) < cacheTTL {
+			c.cache = cached
+			return cached, nil
+		}
+	}
+
+	projects, err := c.source.GetProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := c.source.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache = &cache{
+		UpdatedAt: time.Now(),
+		Projects:  projects,
+		Tasks:     map[string][]clockify.Task{},
+		Tags:      tags,
+	}
+	c.refreshed = true
+	c.writeCache()
+	return c.cache, nil
+}
+
+// tasks returns the tasks of the project, they are fetched lazily for every project.
+func (c *Catalog) tasks(ctx context.Context, projectID string) ([]clockify.Task, error) {
+	cache, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tasks, ok := cache.Tasks[projectID]; ok {
+		return tasks, nil
+	}
+
+	tasks, err := c.source.GetTasks(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if cache.Tasks == nil {
+		cache.Tasks = map[string][]clockify.Task{}
+	}
+	cache.Tasks[projectID] = tasks
+	c.writeCache()
+	return tasks, nil
+}
+
+func (c *Catalog) readCache() (*cache, error) {
+	content, err := os.ReadFile(c.cachePath)
+	if err != nil {
+		return nil, err
+	}
+	var cached cache
+	if err := json.Unmarshal(content, &cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+// writeCache stores the lists, failing to do so only means they are fetched again next time.
+func (c *Catalog) writeCache() {
+	content, err := json.Marshal(c.cache)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.cachePath), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(c.cachePath, content, 0o600)
+}
+
+func projectNames(projects []clockify.Project) []string {
+	names := make([]string, 0, len(projects))
+	for _, project := range projects {
+		names = append(names, project.Name)
+	}
+	return names
+}
+
+func projectIDs(projects []clockify.Project) []string {
+	ids := make([]string, 0, len(projects))
+	for _, project := range projects {
+		ids = append(ids, project.ID)
+	}
+	return ids
+}
+
+// Quality of a match, lower is better
+const (
+	matchExact = iota
+	matchPrefix
+	matchSubstring
+	matchSubsequence
+	noMatch
+)
+
+// match returns the index of the name matching the query best, ignoring case: an exact match, then a prefix,
+// a substring and finally the letters of the query in the same order. A query equal to an ID matches as well.
+// Several names matching equally well are reported as ambiguous.
+func match(kind string, query string, names []string, ids []string) (int, error) {
+	for i, id := range ids {
+		if id == query {
+			return i, nil
+		}
+	}
+
+	normalizedQuery := strings.ToLower(strings.TrimSpace(query))
+	best := noMatch
+	var candidates []int
+	for i, name := range names {
+		quality := matchQuality(normalizedQuery, strings.ToLower(name))
+		switch {
+		case quality < best:
+			best = quality
+			candidates = []int{i}
+		case quality == best && quality != noMatch:
+			candidates = append(candidates, i)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return 0, &noMatchError{kind: kind, query: query}
+	case 1:
+		return candidates[0], nil
+	default:
+		matches := make([]string, 0, len(candidates))
+		for _, i := range candidates {
+			matches = append(matches, "'"+names[i]+"'")
+		}
+		return 0, fmt.Errorf("%s '%s' is ambiguous, it matches %s", kind, query, strings.Join(matches, ", "))
+	}
+}
+
+func matchQuality(query string, name string) int {
+	switch {
+	case name == query:
+		return matchExact
+	case strings.HasPrefix(name, query):
+		return matchPrefix
+	case strings.Contains(name, query):
+		return matchSubstring
+	case isSubsequence(query, name):
+		return matchSubsequence
+	default:
+		return noMatch
+	}
+}
+
+func isSubsequence(query string, name string) bool {
+	remaining := []rune(query)
+	for _, r := range name {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
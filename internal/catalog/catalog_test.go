@@ -0,0 +1,144 @@
+package catalog
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andrejsoucek/chronos/pkg/clockify"
+)
+
+// fakeSource serves fixed lists and counts how often they are fetched.
+type fakeSource struct {
+	projects     []clockify.Project
+	tasks        map[string][]clockify.Task
+	tags         []clockify.Tag
+	projectCalls int
+	taskCalls    int
+}
+
+func (s *fakeSource) GetProjects(ctx context.Context) ([]clockify.Project, error) {
+	s.projectCalls++
+	return s.projects, nil
+}
+
+func (s *fakeSource) GetTasks(ctx context.Context, projectID string) ([]clockify.Task, error) {
+	s.taskCalls++
+	return s.tasks[projectID], nil
+}
+
+func (s *fakeSource) GetTags(ctx context.Context) ([]clockify.Tag, error) {
+	return s.tags, nil
+}
+
+func TestMatch(t *testing.T) {
+	names := []string{"Backend", "Backend API", "Frontend", "Internal meetings", "Mobile app", "Mobile web"}
+	ids := []string{"p1", "p2", "p3", "p4", "p5", "p6"}
+
+	tests := []struct {
+		query   string
+		want    string
+		wantErr string
+	}{
+		{query: "p3", want: "Frontend"},                      // ID
+		{query: "backend", want: "Backend"},                  // Exact before the prefix of Backend API
+		{query: "back", wantErr: "'Backend', 'Backend API'"}, // Two prefixes
+		{query: "front", want: "Frontend"},                   // Prefix
+		{query: "meet", want: "Internal meetings"},           // Substring
+		{query: "end api", want: "Backend API"},              // Substring, before the subsequence of Frontend
+		{query: "intmtg", want: "Internal meetings"},         // Subsequence
+		{query: " FRONTEND ", want: "Frontend"},              // Case and surrounding spaces are ignored
+		{query: "mobile", wantErr: "project 'mobile' is ambiguous, it matches 'Mobile app', 'Mobile web'"},
+		{query: "design", wantErr: "no project matches 'design'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			i, err := match("project", tt.query, names, ids)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("match() = %v, want an error with %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("match() failed: %v", err)
+			}
+			if names[i] != tt.want {
+				t.Errorf("match() = %s, want %s", names[i], tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	source := &fakeSource{projects: []clockify.Project{{ID: "p1", Name: "Backend"}}}
+
+	project, err := New(source, path).Project(context.Background(), "back")
+	if err != nil || project.ID != "p1" {
+		t.Fatalf("Project() = %+v, %v, want Backend", project, err)
+	}
+
+	// Another run reads the cache file instead of fetching the projects
+	if _, err := New(source, path).Project(context.Background(), "backend"); err != nil {
+		t.Fatalf("Project() failed: %v", err)
+	}
+	if source.projectCalls != 1 {
+		t.Errorf("projects were fetched %d times, want once", source.projectCalls)
+	}
+}
+
+func TestCatalogRefreshesMissingNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	source := &fakeSource{projects: []clockify.Project{{ID: "p1", Name: "Backend"}}}
+	if _, err := New(source, path).Project(context.Background(), "backend"); err != nil {
+		t.Fatalf("Project() failed: %v", err)
+	}
+
+	// A project created after the cache was written is found by fetching the projects once more
+	source.projects = append(source.projects, clockify.Project{ID: "p2", Name: "Frontend"})
+	c := New(source, path)
+	project, err := c.Project(context.Background(), "front")
+	if err != nil || project.ID != "p2" {
+		t.Fatalf("Project() = %+v, %v, want Frontend", project, err)
+	}
+	if source.projectCalls != 2 {
+		t.Errorf("projects were fetched %d times, want twice", source.projectCalls)
+	}
+
+	// The lists are fresh now, so a name that does not exist does not fetch them again
+	if _, err := c.Project(context.Background(), "design"); err == nil || err.Error() != "no project matches 'design'" {
+		t.Errorf("Project() = %v, want no match", err)
+	}
+	if source.projectCalls != 2 {
+		t.Errorf("projects were fetched %d times, want twice", source.projectCalls)
+	}
+}
+
+func TestCatalogTasksAndTags(t *testing.T) {
+	source := &fakeSource{
+		projects: []clockify.Project{{ID: "p1", Name: "Backend"}},
+		tasks:    map[string][]clockify.Task{"p1": {{ID: "t1", Name: "Code review"}, {ID: "t2", Name: "Deployment"}}},
+		tags:     []clockify.Tag{{ID: "g1", Name: "Overtime"}, {ID: "g2", Name: "On call"}},
+	}
+	c := New(source, filepath.Join(t.TempDir(), "cache.json"))
+
+	for _, query := range []string{"review", "deploy"} {
+		if _, err := c.Task(context.Background(), "p1", query); err != nil {
+			t.Fatalf("Task(%s) failed: %v", query, err)
+		}
+	}
+	if source.taskCalls != 1 {
+		t.Errorf("tasks were fetched %d times, want once", source.taskCalls)
+	}
+
+	tag, err := c.Tag(context.Background(), "g2")
+	if err != nil || tag.Name != "On call" {
+		t.Errorf("Tag() = %+v, %v, want On call", tag, err)
+	}
+	if _, err := c.Tag(context.Background(), "o"); err == nil || !strings.Contains(err.Error(), "tag 'o' is ambiguous") {
+		t.Errorf("Tag() = %v, want an ambiguous tag", err)
+	}
+}
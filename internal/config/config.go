@@ -0,0 +1,525 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/andrejsoucek/chronos/pkg/holidays"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+const (
+	defaultClockifyURL = "https://api.clockify.me/api/v1/"
+	defaultTogglURL    = "https://api.track.toggl.com/api/v9/"
+	defaultHarvestURL  = "https://api.harvestapp.com/v2/"
+	defaultGitlabURL   = "https://gitlab.com/api/v4/"
+	defaultLinearURL   = "https://api.linear.app/graphql"
+)
+
+var ErrNotFound = errors.New("config file not found")
+
+// Config is the content of the config file, every profile holds a complete configuration of chronos.
+type Config struct {
+	DefaultProfile string              `toml:"default_profile"`
+	Profiles       map[string]*Profile `toml:"profiles"`
+}
+
+type Profile struct {
+	Backend        string         `toml:"backend,omitempty"`
+	DefaultProject string         `toml:"default_project,omitempty"`
+	Clockify       ClockifyConfig `toml:"clockify,omitempty"`
+	Toggl          TogglConfig    `toml:"toggl,omitempty"`
+	Harvest        HarvestConfig  `toml:"harvest,omitempty"`
+	Gitlab         GitlabConfig   `toml:"gitlab,omitempty"`
+	Linear         LinearConfig   `toml:"linear,omitempty"`
+	Report         ReportConfig   `toml:"report,omitempty"`
+	Rounding       RoundingConfig `toml:"rounding,omitempty"`
+	Targets        TargetsConfig  `toml:"targets,omitempty"`
+	Holidays       HolidaysConfig `toml:"holidays,omitempty"`
+}
+
+// The API keys can be given directly or read from the output of a *_cmd command, the first line of a *_file file
+// or the OS keyring entry named by *_keyring.
+
+type ClockifyConfig struct {
+	APIKey        string `toml:"api_key,omitempty"`
+	APIKeyCmd     string `toml:"api_key_cmd,omitempty"`
+	APIKeyFile    string `toml:"api_key_file,omitempty"`
+	APIKeyKeyring string `toml:"api_key_keyring,omitempty"`
+	WorkspaceID   string `toml:"workspace_id,omitempty"`
+	UserID        string `toml:"user_id,omitempty"`
+	BaseURL       string `toml:"base_url,omitempty"` // Workspace URL, derived from the workspace ID when empty
+	UserURL       string `toml:"user_url,omitempty"`
+}
+
+type TogglConfig struct {
+	APIKey        string `toml:"api_key,omitempty"`
+	APIKeyCmd     string `toml:"api_key_cmd,omitempty"`
+	APIKeyFile    string `toml:"api_key_file,omitempty"`
+	APIKeyKeyring string `toml:"api_key_keyring,omitempty"`
+	WorkspaceID   string `toml:"workspace_id,omitempty"`
+	BaseURL       string `toml:"base_url,omitempty"`
+}
+
+type HarvestConfig struct {
+	AccessToken        string `toml:"access_token,omitempty"`
+	AccessTokenCmd     string `toml:"access_token_cmd,omitempty"`
+	AccessTokenFile    string `toml:"access_token_file,omitempty"`
+	AccessTokenKeyring string `toml:"access_token_keyring,omitempty"`
+	AccountID          string `toml:"account_id,omitempty"`
+	BaseURL            string `toml:"base_url,omitempty"`
+}
+
+type GitlabConfig struct {
+	AccessToken        string `toml:"access_token,omitempty"`
+	AccessTokenCmd     string `toml:"access_token_cmd,omitempty"`
+	AccessTokenFile    string `toml:"access_token_file,omitempty"`
+	AccessTokenKeyring string `toml:"access_token_keyring,omitempty"`
+	UserID             string `toml:"user_id,omitempty"`
+	BaseURL            string `toml:"base_url,omitempty"`
+}
+
+type LinearConfig struct {
+	APIKey        string `toml:"api_key,omitempty"`
+	APIKeyCmd     string `toml:"api_key_cmd,omitempty"`
+	APIKeyFile    string `toml:"api_key_file,omitempty"`
+	APIKeyKeyring string `toml:"api_key_keyring,omitempty"`
+	BaseURL       string `toml:"base_url,omitempty"`
+}
+
+// ReportConfig controls how the report places edited and new entries.
+type ReportConfig struct {
+	EditKeeps    string `toml:"edit_keeps,omitempty"`    // "start" (default) or "end" of an entry kept when its duration changes
+	WorkdayStart string `toml:"workday_start,omitempty"` // HH:MM, new entries of a day follow each other from this time
+}
+
+// RoundingRule rounds logged time, e.g. granularity = "15m", mode = "up" and apply_to = "duration".
+type RoundingRule struct {
+	Granularity string `toml:"granularity,omitempty"` // Go duration, rounding is off when empty
+	Mode        string `toml:"mode,omitempty"`        // "nearest" (default), "up", "down" or "none"
+	ApplyTo     string `toml:"apply_to,omitempty"`    // "start", "end" (default) or "duration"
+}
+
+// RoundingConfig is the rounding of the profile, projects given by their ID can override any of its fields.
+type RoundingConfig struct {
+	RoundingRule
+	Projects map[string]RoundingRule `toml:"projects,omitempty"`
+}
+
+// TargetsConfig is the working time expected per weekday. Daily applies from Monday to Friday unless the weekday
+// sets its own, e.g. friday = "4h" for a part-time schedule.
+type TargetsConfig struct {
+	Daily     string `toml:"daily,omitempty"`
+	Monday    string `toml:"monday,omitempty"`
+	Tuesday   string `toml:"tuesday,omitempty"`
+	Wednesday string `toml:"wednesday,omitempty"`
+	Thursday  string `toml:"thursday,omitempty"`
+	Friday    string `toml:"friday,omitempty"`
+	Saturday  string `toml:"saturday,omitempty"`
+	Sunday    string `toml:"sunday,omitempty"`
+}
+
+// HolidaysConfig lists the non-working days besides the weekends.
+type HolidaysConfig struct {
+	Country string   `toml:"country,omitempty"`  // Built-in public holidays, e.g. "CZ"
+	ICSFile string   `toml:"ics_file,omitempty"` // Calendar with the holidays as all-day events
+	Leave   []string `toml:"leave,omitempty"`    // Personal days off, YYYY-MM-DD or YYYY-MM-DD..YYYY-MM-DD
+}
+
+// DefaultPath returns the location of the config file, $XDG_CONFIG_HOME/chronos/config.toml
+// or ~/.config/chronos/config.toml when the variable is not set.
+func DefaultPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(configHome, "chronos", "config.toml"), nil
+}
+
+// Load reads the config file, ErrNotFound is returned when it does not exist.
+func Load(path string) (*Config, error) {
+	var config Config
+	if _, err := toml.DecodeFile(path, &config); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+	return &config, nil
+}
+
+// Profile returns the profile with the given name. Without a name the default profile is used, or the only one
+// when the config file has just a single profile.
+func (c *Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" && len(c.Profiles) == 1 {
+		for profileName := range c.Profiles {
+			name = profileName
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("no profile selected, set default_profile or use one of the profiles: %v", c.ProfileNames())
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile '%s' not found, available profiles: %v", name, c.ProfileNames())
+	}
+	profile.ApplyDefaults()
+	return profile, nil
+}
+
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FromEnv creates a profile from the environment variables of the legacy .env configuration.
+func FromEnv() *Profile {
+	// The example configuration used to name the variable CLOCKIFY_DEFAULT_WORKSPACE while CLOCKIFY_WORKSPACE
+	// was read, so both are accepted
+	workspaceID := os.Getenv("CLOCKIFY_WORKSPACE")
+	if workspaceID == "" {
+		workspaceID = os.Getenv("CLOCKIFY_DEFAULT_WORKSPACE")
+	}
+
+	profile := &Profile{
+		Backend: os.Getenv("CHRONOS_BACKEND"),
+		Clockify: ClockifyConfig{
+			APIKey:      os.Getenv("CLOCKIFY_API_KEY"),
+			WorkspaceID: workspaceID,
+			UserID:      os.Getenv("CLOCKIFY_USER_ID"),
+			BaseURL:     os.Getenv("CLOCKIFY_BASE_URL"),
+			UserURL:     os.Getenv("CLOCKIFY_USER_URL"),
+		},
+		Toggl: TogglConfig{
+			APIKey:      os.Getenv("TOGGL_API_KEY"),
+			WorkspaceID: os.Getenv("TOGGL_WORKSPACE_ID"),
+			BaseURL:     os.Getenv("TOGGL_BASE_URL"),
+		},
+		Harvest: HarvestConfig{
+			AccessToken: os.Getenv("HARVEST_ACCESS_TOKEN"),
+			AccountID:   os.Getenv("HARVEST_ACCOUNT_ID"),
+			BaseURL:     os.Getenv("HARVEST_BASE_URL"),
+		},
+		Gitlab: GitlabConfig{
+			AccessToken: os.Getenv("GITLAB_ACCESS_TOKEN"),
+			UserID:      os.Getenv("GITLAB_USER_ID"),
+			BaseURL:     os.Getenv("GITLAB_BASE_URL"),
+		},
+		Linear: LinearConfig{
+			APIKey:  os.Getenv("LINEAR_API_KEY"),
+			BaseURL: os.Getenv("LINEAR_BASE_URL"),
+		},
+	}
+
+	switch profile.Backend {
+	case "toggl":
+		profile.DefaultProject = os.Getenv("TOGGL_DEFAULT_PROJECT")
+	case "harvest":
+		profile.DefaultProject = os.Getenv("HARVEST_DEFAULT_PROJECT")
+	default:
+		profile.DefaultProject = os.Getenv("CLOCKIFY_DEFAULT_PROJECT")
+	}
+
+	profile.ApplyDefaults()
+	return profile
+}
+
+// Save writes the config file readable by the owner only, as it contains the API keys.
+func Save(path string, config *Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := toml.NewEncoder(file).Encode(config); err != nil {
+		return fmt.Errorf("failed to write config file %s: %v", path, err)
+	}
+	return file.Close()
+}
+
+// Validate checks that the profile contains everything the selected backend and the enabled integrations need.
+func (p *Profile) Validate() error {
+	var errs []error
+	required := func(name string, value string) {
+		if value == "" {
+			errs = append(errs, fmt.Errorf("%s is not set", name))
+		}
+	}
+
+	switch p.Backend {
+	case "clockify":
+		required("clockify.workspace_id", p.Clockify.WorkspaceID)
+		required("clockify.user_id", p.Clockify.UserID)
+	case "toggl":
+		required("toggl.workspace_id", p.Toggl.WorkspaceID)
+	case "harvest":
+		required("harvest.account_id", p.Harvest.AccountID)
+	default:
+		errs = append(errs, fmt.Errorf("unknown time tracking backend '%s'", p.Backend))
+	}
+	required("default_project", p.DefaultProject)
+
+	if s, ok := p.backendSecret(); ok && !s.isSet() {
+		errs = append(errs, fmt.Errorf("%s is not set, neither directly nor by a command, file or keyring", s.name))
+	}
+	if p.gitlabSecret().isSet() {
+		required("gitlab.user_id", p.Gitlab.UserID)
+	}
+	if err := p.Report.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := p.RoundingPolicy(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := p.weekdayTargets(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := p.Calendar(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *ReportConfig) Validate() error {
+	var errs []error
+	switch r.EditKeeps {
+	case "", "start", "end":
+	default:
+		errs = append(errs, fmt.Errorf("report.edit_keeps must be 'start' or 'end', not '%s'", r.EditKeeps))
+	}
+	if r.WorkdayStart != "" {
+		if _, err := time.Parse("15:04", r.WorkdayStart); err != nil {
+			errs = append(errs, fmt.Errorf("report.workday_start must be in the HH:MM format, not '%s'", r.WorkdayStart))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RoundingPolicy returns the rounding of logged time, nil when the profile does not configure any.
+func (p *Profile) RoundingPolicy() (*tracker.RoundingPolicy, error) {
+	if p.Rounding.RoundingRule == (RoundingRule{}) && len(p.Rounding.Projects) == 0 {
+		return nil, nil
+	}
+
+	policy := &tracker.RoundingPolicy{
+		Projects: make(map[string]tracker.Rounding, len(p.Rounding.Projects)),
+	}
+	// Without a granularity or mode none of its own the rounding section only provides the defaults of the project rules
+	if p.Rounding.Granularity != "" || p.Rounding.Mode == string(tracker.RoundNone) || len(p.Rounding.Projects) == 0 {
+		defaultRounding, err := p.Rounding.rounding(RoundingRule{}, "rounding")
+		if err != nil {
+			return nil, err
+		}
+		policy.Default = &defaultRounding
+	}
+	for projectID, rule := range p.Rounding.Projects {
+		rounding, err := rule.rounding(p.Rounding.RoundingRule, "rounding.projects."+projectID)
+		if err != nil {
+			return nil, err
+		}
+		policy.Projects[projectID] = rounding
+	}
+	return policy, nil
+}
+
+// rounding converts the rule, its empty fields are taken from the parent rule or the defaults.
+func (r RoundingRule) rounding(parent RoundingRule, name string) (tracker.Rounding, error) {
+	rounding := tracker.Rounding{
+		Mode:   tracker.RoundingMode(firstNonEmpty(r.Mode, parent.Mode, string(tracker.RoundNearest))),
+		Target: tracker.RoundingTarget(firstNonEmpty(r.ApplyTo, parent.ApplyTo, string(tracker.RoundEnd))),
+	}
+
+	granularity := firstNonEmpty(r.Granularity, parent.Granularity)
+	switch {
+	case granularity != "":
+		duration, err := time.ParseDuration(granularity)
+		if err != nil {
+			return rounding, fmt.Errorf("%s.granularity must be a duration like 15m, not '%s'", name, granularity)
+		}
+		rounding.Granularity = duration
+	case rounding.Mode != tracker.RoundNone:
+		return rounding, fmt.Errorf("%s.granularity is not set", name)
+	}
+
+	if err := rounding.Validate(); err != nil {
+		return rounding, fmt.Errorf("%s: %v", name, err)
+	}
+	return rounding, nil
+}
+
+// Schedule returns the working time expected per weekday with the holidays and leave as days off, nil when the profile
+// does not configure any targets.
+func (p *Profile) Schedule() (*tracker.Schedule, error) {
+	schedule, err := p.weekdayTargets()
+	if schedule == nil || err != nil {
+		return nil, err
+	}
+	calendar, err := p.Calendar()
+	if err != nil {
+		return nil, err
+	}
+	if calendar != nil {
+		schedule.DaysOff = calendar
+	}
+	return schedule, nil
+}
+
+func (p *Profile) weekdayTargets() (*tracker.Schedule, error) {
+	if p.Targets == (TargetsConfig{}) {
+		return nil, nil
+	}
+
+	weekdays := []struct {
+		day   time.Weekday
+		name  string
+		value string
+	}{
+		{time.Monday, "monday", p.Targets.Monday},
+		{time.Tuesday, "tuesday", p.Targets.Tuesday},
+		{time.Wednesday, "wednesday", p.Targets.Wednesday},
+		{time.Thursday, "thursday", p.Targets.Thursday},
+		{time.Friday, "friday", p.Targets.Friday},
+		{time.Saturday, "saturday", p.Targets.Saturday},
+		{time.Sunday, "sunday", p.Targets.Sunday},
+	}
+
+	var errs []error
+	parse := func(name string, value string) time.Duration {
+		if value == "" {
+			return 0
+		}
+		target, err := time.ParseDuration(value)
+		if err != nil || target < 0 || target > 24*time.Hour {
+			errs = append(errs, fmt.Errorf("targets.%s must be a duration between 0h and 24h, not '%s'", name, value))
+		}
+		return target
+	}
+
+	var schedule tracker.Schedule
+	daily := parse("daily", p.Targets.Daily)
+	for _, weekday := range weekdays {
+		if weekday.day != time.Saturday && weekday.day != time.Sunday {
+			schedule.Weekdays[weekday.day] = daily
+		}
+		if weekday.value != "" {
+			schedule.Weekdays[weekday.day] = parse(weekday.name, weekday.value)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return &schedule, nil
+}
+
+// Calendar returns the public holidays and the leave of the profile, nil when it configures none.
+func (p *Profile) Calendar() (*holidays.Calendar, error) {
+	if p.Holidays.Country == "" && p.Holidays.ICSFile == "" && len(p.Holidays.Leave) == 0 {
+		return nil, nil
+	}
+
+	calendar := holidays.NewCalendar()
+	if p.Holidays.Country != "" {
+		if err := calendar.AddCountry(p.Holidays.Country); err != nil {
+			return nil, fmt.Errorf("holidays.country: %v", err)
+		}
+	}
+	if p.Holidays.ICSFile != "" {
+		events, err := holidays.LoadICS(expandHome(p.Holidays.ICSFile))
+		if err != nil {
+			return nil, fmt.Errorf("holidays.ics_file: %v", err)
+		}
+		calendar.AddEvents(events)
+	}
+	for _, leave := range p.Holidays.Leave {
+		firstDate, lastDate, isRange := strings.Cut(leave, "..")
+		if !isRange {
+			lastDate = firstDate
+		}
+		first, err := time.Parse("2006-01-02", strings.TrimSpace(firstDate))
+		if err != nil {
+			return nil, fmt.Errorf("holidays.leave must contain YYYY-MM-DD or YYYY-MM-DD..YYYY-MM-DD, not '%s'", leave)
+		}
+		last, err := time.Parse("2006-01-02", strings.TrimSpace(lastDate))
+		if err != nil || last.Before(first) {
+			return nil, fmt.Errorf("holidays.leave must contain YYYY-MM-DD or YYYY-MM-DD..YYYY-MM-DD, not '%s'", leave)
+		}
+		calendar.AddLeave(first, last)
+	}
+	return calendar, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Redacted returns a copy of the profile that can be shown, with the API keys hidden.
+func (p *Profile) Redacted() *Profile {
+	redacted := *p
+	redacted.Clockify.APIKey = redact(p.Clockify.APIKey)
+	redacted.Toggl.APIKey = redact(p.Toggl.APIKey)
+	redacted.Harvest.AccessToken = redact(p.Harvest.AccessToken)
+	redacted.Gitlab.AccessToken = redact(p.Gitlab.AccessToken)
+	redacted.Linear.APIKey = redact(p.Linear.APIKey)
+	return &redacted
+}
+
+// ApplyDefaults fills in the default API URLs and backend.
+func (p *Profile) ApplyDefaults() {
+	if p.Backend == "" {
+		p.Backend = "clockify"
+	}
+	if p.Clockify.BaseURL == "" && p.Clockify.WorkspaceID != "" {
+		p.Clockify.BaseURL = defaultClockifyURL + "workspaces/" + p.Clockify.WorkspaceID + "/"
+	}
+	if p.Clockify.UserURL == "" {
+		p.Clockify.UserURL = defaultClockifyURL + "user"
+	}
+	if p.Toggl.BaseURL == "" {
+		p.Toggl.BaseURL = defaultTogglURL
+	}
+	if p.Harvest.BaseURL == "" {
+		p.Harvest.BaseURL = defaultHarvestURL
+	}
+	if p.Gitlab.BaseURL == "" {
+		p.Gitlab.BaseURL = defaultGitlabURL
+	}
+	if p.Linear.BaseURL == "" {
+		p.Linear.BaseURL = defaultLinearURL
+	}
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "********"
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+func TestRoundingPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		rounding    RoundingConfig
+		wantDefault *tracker.Rounding
+		wantProject *tracker.Rounding
+		wantErr     bool
+	}{
+		{
+			name: "default only",
+			rounding: RoundingConfig{
+				RoundingRule: RoundingRule{Granularity: "15m"},
+			},
+			wantDefault: &tracker.Rounding{Granularity: 15 * time.Minute, Mode: tracker.RoundNearest, Target: tracker.RoundEnd},
+		},
+		{
+			name: "projects only",
+			rounding: RoundingConfig{
+				Projects: map[string]RoundingRule{"p1": {Granularity: "6m", ApplyTo: "duration"}},
+			},
+			wantProject: &tracker.Rounding{Granularity: 6 * time.Minute, Mode: tracker.RoundNearest, Target: tracker.RoundDuration},
+		},
+		{
+			name: "projects inherit the mode",
+			rounding: RoundingConfig{
+				RoundingRule: RoundingRule{Mode: "up"},
+				Projects:     map[string]RoundingRule{"p1": {Granularity: "6m"}},
+			},
+			wantProject: &tracker.Rounding{Granularity: 6 * time.Minute, Mode: tracker.RoundUp, Target: tracker.RoundEnd},
+		},
+		{
+			name: "default without granularity",
+			rounding: RoundingConfig{
+				RoundingRule: RoundingRule{Mode: "up"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "project without granularity",
+			rounding: RoundingConfig{
+				Projects: map[string]RoundingRule{"p1": {Mode: "down"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &Profile{Rounding: tt.rounding}
+			policy, err := profile.RoundingPolicy()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("RoundingPolicy() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RoundingPolicy() failed: %v", err)
+			}
+			if got := policy.For("other"); !equalRounding(got, tt.wantDefault) {
+				t.Errorf("For(other) = %v, want %v", got, tt.wantDefault)
+			}
+			if tt.wantProject != nil {
+				if got := policy.For("p1"); !equalRounding(got, tt.wantProject) {
+					t.Errorf("For(p1) = %v, want %v", got, tt.wantProject)
+				}
+			}
+		})
+	}
+}
+
+func equalRounding(a *tracker.Rounding, b *tracker.Rounding) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
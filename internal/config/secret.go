@@ -0,0 +1,178 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Name of the keyring service the secrets are stored under
+const keyringService = "chronos"
+
+// secret is an API key given directly in the config file or read from a command, a file or the OS keyring.
+type secret struct {
+	name    string
+	value   *string
+	cmd     string
+	file    string
+	keyring string
+}
+
+// ResolveBackendSecret reads the API key of the selected backend when it is not given directly.
+func (p *Profile) ResolveBackendSecret(ctx context.Context) error {
+	s, ok := p.backendSecret()
+	if !ok {
+		return nil
+	}
+	return s.resolveInto(ctx)
+}
+
+// ResolveGitlabSecret reads the GitLab access token when it is not given directly, it is needed by the report only.
+func (p *Profile) ResolveGitlabSecret(ctx context.Context) error {
+	return p.gitlabSecret().resolveInto(ctx)
+}
+
+// ResolveLinearSecret reads the Linear API key when it is not given directly, it is needed by the report only.
+func (p *Profile) ResolveLinearSecret(ctx context.Context) error {
+	return p.linearSecret().resolveInto(ctx)
+}
+
+func (p *Profile) backendSecret() (secret, bool) {
+	switch p.Backend {
+	case "clockify":
+		c := &p.Clockify
+		return secret{"clockify.api_key", &c.APIKey, c.APIKeyCmd, c.APIKeyFile, c.APIKeyKeyring}, true
+	case "toggl":
+		t := &p.Toggl
+		return secret{"toggl.api_key", &t.APIKey, t.APIKeyCmd, t.APIKeyFile, t.APIKeyKeyring}, true
+	case "harvest":
+		h := &p.Harvest
+		return secret{"harvest.access_token", &h.AccessToken, h.AccessTokenCmd, h.AccessTokenFile, h.AccessTokenKeyring}, true
+	default:
+		return secret{}, false
+	}
+}
+
+func (p *Profile) gitlabSecret() secret {
+	g := &p.Gitlab
+	return secret{"gitlab.access_token", &g.AccessToken, g.AccessTokenCmd, g.AccessTokenFile, g.AccessTokenKeyring}
+}
+
+func (p *Profile) linearSecret() secret {
+	l := &p.Linear
+	return secret{"linear.api_key", &l.APIKey, l.APIKeyCmd, l.APIKeyFile, l.APIKeyKeyring}
+}
+
+func (s secret) isSet() bool {
+	return *s.value != "" || s.cmd != "" || s.file != "" || s.keyring != ""
+}
+
+// resolveInto stores the secret read from its source in the profile, a value given directly is kept.
+func (s secret) resolveInto(ctx context.Context) error {
+	if *s.value != "" {
+		return nil
+	}
+	value, err := s.resolve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", s.name, err)
+	}
+	*s.value = value
+	return nil
+}
+
+func (s secret) resolve(ctx context.Context) (string, error) {
+	switch {
+	case s.cmd != "":
+		return runSecretCommand(ctx, s.cmd)
+	case s.file != "":
+		return readSecretFile(s.file)
+	case s.keyring != "":
+		return readKeyring(ctx, s.keyring)
+	default:
+		return "", nil
+	}
+}
+
+func runSecretCommand(ctx context.Context, command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	return runCommand(cmd)
+}
+
+// readSecretFile reads the secret from the first line of the file, which must not be accessible by other users.
+func readSecretFile(path string) (string, error) {
+	path = expandHome(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	// Windows does not have Unix permissions
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("permissions %04o of %s are too open, restrict them with chmod 600", info.Mode().Perm(), path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	firstLine, _, _ := strings.Cut(string(content), "\n")
+	value := strings.TrimSpace(firstLine)
+	if value == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return value, nil
+}
+
+// readKeyring looks the secret up in the Secret Service keyring on Linux or the login keychain on macOS, stored
+// under the chronos service with the given key as the account.
+func readKeyring(ctx context.Context, key string) (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return runCommand(exec.CommandContext(ctx, "security", "find-generic-password", "-s", keyringService, "-a", key, "-w"))
+	case "linux", "freebsd", "openbsd":
+		return runCommand(exec.CommandContext(ctx, "secret-tool", "lookup", "service", keyringService, "account", key))
+	default:
+		return "", fmt.Errorf("keyring is not supported on %s, use a command or a file instead", runtime.GOOS)
+	}
+}
+
+// runCommand runs the command and returns the first line of its output.
+func runCommand(cmd *exec.Cmd) (string, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("'%s' failed: %v: %s", strings.Join(cmd.Args, " "), err, strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("'%s' failed: %v", strings.Join(cmd.Args, " "), err)
+	}
+
+	firstLine, _, _ := strings.Cut(string(output), "\n")
+	value := strings.TrimSpace(firstLine)
+	if value == "" {
+		return "", fmt.Errorf("'%s' returned no secret", strings.Join(cmd.Args, " "))
+	}
+	return value, nil
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, rest)
+		}
+	}
+	return path
+}
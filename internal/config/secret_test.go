@@ -0,0 +1,191 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake secret commands are shell scripts")
+	}
+}
+
+// writeFile creates a file in a temporary directory with the given permissions.
+func writeFile(t *testing.T, name string, content string, perm os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+	// WriteFile applies the umask
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveBackendSecretCommand(t *testing.T) {
+	skipOnWindows(t)
+	tests := []struct {
+		name    string
+		cmd     string
+		want    string
+		wantErr string
+	}{
+		{name: "first line", cmd: "printf ' token \\nsecond line\\n'", want: "token"},
+		{name: "failing command", cmd: "echo denied >&2; exit 3", wantErr: "denied"},
+		{name: "empty output", cmd: "true", wantErr: "returned no secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &Profile{Backend: "clockify", Clockify: ClockifyConfig{APIKeyCmd: tt.cmd}}
+			err := profile.ResolveBackendSecret(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "clockify.api_key") {
+					t.Fatalf("ResolveBackendSecret() = %v, want an error about %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveBackendSecret() failed: %v", err)
+			}
+			if profile.Clockify.APIKey != tt.want {
+				t.Errorf("api_key = %q, want %q", profile.Clockify.APIKey, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveBackendSecretKeepsValue(t *testing.T) {
+	profile := &Profile{Backend: "toggl", Toggl: TogglConfig{APIKey: "direct", APIKeyCmd: "exit 1"}}
+	if err := profile.ResolveBackendSecret(context.Background()); err != nil {
+		t.Fatalf("ResolveBackendSecret() failed: %v", err)
+	}
+	if profile.Toggl.APIKey != "direct" {
+		t.Errorf("api_key = %q, want the value given directly", profile.Toggl.APIKey)
+	}
+}
+
+func TestResolveBackendSecretFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		perm    os.FileMode
+		want    string
+		wantErr string
+		unix    bool // Permissions are only checked on Unix
+	}{
+		{name: "owner only", content: "token\nignored\n", perm: 0o600, want: "token"},
+		{name: "read only", content: " token \n", perm: 0o400, want: "token"},
+		{name: "group readable", content: "token\n", perm: 0o640, wantErr: "too open", unix: true},
+		{name: "world readable", content: "token\n", perm: 0o604, wantErr: "too open", unix: true},
+		{name: "empty", content: "\n", perm: 0o600, wantErr: "is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.unix && runtime.GOOS == "windows" {
+				t.Skip("Windows does not have Unix permissions")
+			}
+			path := writeFile(t, "token", tt.content, tt.perm)
+			profile := &Profile{Backend: "harvest", Harvest: HarvestConfig{AccessTokenFile: path}}
+			err := profile.ResolveBackendSecret(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveBackendSecret() = %v, want an error about %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveBackendSecret() failed: %v", err)
+			}
+			if profile.Harvest.AccessToken != tt.want {
+				t.Errorf("access_token = %q, want %q", profile.Harvest.AccessToken, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveBackendSecretFileInHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := os.WriteFile(filepath.Join(home, "token"), []byte("token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	profile := &Profile{Backend: "clockify", Clockify: ClockifyConfig{APIKeyFile: "~/token"}}
+	if err := profile.ResolveBackendSecret(context.Background()); err != nil {
+		t.Fatalf("ResolveBackendSecret() failed: %v", err)
+	}
+	if profile.Clockify.APIKey != "token" {
+		t.Errorf("api_key = %q, want token", profile.Clockify.APIKey)
+	}
+}
+
+func TestResolveBackendSecretKeyring(t *testing.T) {
+	var tool string
+	switch runtime.GOOS {
+	case "darwin":
+		tool = "security"
+	case "linux", "freebsd", "openbsd":
+		tool = "secret-tool"
+	default:
+		t.Skipf("keyring is not supported on %s", runtime.GOOS)
+	}
+
+	// A fake keyring tool printing its arguments, so that the lookup can be checked
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho \"$*\"\n"
+	if err := os.WriteFile(filepath.Join(dir, tool), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	profile := &Profile{Backend: "clockify", Clockify: ClockifyConfig{APIKeyKeyring: "work"}}
+	if err := profile.ResolveBackendSecret(context.Background()); err != nil {
+		t.Fatalf("ResolveBackendSecret() failed: %v", err)
+	}
+	want := "lookup service chronos account work"
+	if tool == "security" {
+		want = "find-generic-password -s chronos -a work -w"
+	}
+	if profile.Clockify.APIKey != want {
+		t.Errorf("keyring was called with %q, want %q", profile.Clockify.APIKey, want)
+	}
+}
+
+func TestResolveSecretsOfIntegrationsSeparately(t *testing.T) {
+	skipOnWindows(t)
+	marker := filepath.Join(t.TempDir(), "ran")
+	profile := &Profile{
+		Backend:  "clockify",
+		Clockify: ClockifyConfig{APIKey: "direct"},
+		Gitlab:   GitlabConfig{AccessTokenCmd: "touch " + marker + "; echo gitlab"},
+		Linear:   LinearConfig{APIKeyCmd: "touch " + marker + "; echo linear"},
+	}
+
+	if err := profile.ResolveBackendSecret(context.Background()); err != nil {
+		t.Fatalf("ResolveBackendSecret() failed: %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatal("the secret commands of the integrations ran for the backend")
+	}
+
+	if err := profile.ResolveGitlabSecret(context.Background()); err != nil {
+		t.Fatalf("ResolveGitlabSecret() failed: %v", err)
+	}
+	if err := profile.ResolveLinearSecret(context.Background()); err != nil {
+		t.Fatalf("ResolveLinearSecret() failed: %v", err)
+	}
+	if profile.Gitlab.AccessToken != "gitlab" || profile.Linear.APIKey != "linear" {
+		t.Errorf("secrets = %q and %q, want gitlab and linear", profile.Gitlab.AccessToken, profile.Linear.APIKey)
+	}
+}
@@ -1,12 +1,16 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"sort"
+	"sync"
 	"time"
 
-	"github.com/andrejsoucek/chronos/pkg/clockify"
 	"github.com/andrejsoucek/chronos/pkg/datetimeutils"
+	"github.com/andrejsoucek/chronos/pkg/gitlab"
+	"github.com/andrejsoucek/chronos/pkg/linear"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
 	"github.com/jroimartin/gocui"
 )
 
@@ -15,11 +19,22 @@ func (ui *ReportUI) editCell(g *gocui.Gui, v *gocui.View) error {
 		return ui.saveEdit(g, v)
 	}
 
-	ui.isEditing = true
-	task := ui.taskNames[ui.selectedCell.TaskIndex]
+	if len(ui.rows) == 0 || ui.isBusy() {
+		return nil
+	}
+
+	row := ui.rows[ui.selectedCell.TaskIndex]
 	day := ui.days[ui.selectedCell.DayIndex]
+	entries := ui.cells[row][day]
+
+	// The total of several entries cannot be split between them, they are edited one by one
+	if len(entries) > 1 {
+		ui.logInfo(fmt.Sprintf("The cell has %d entries, edit them one by one or merge them with M", len(entries)))
+		return ui.showEntries(g, v)
+	}
 
-	if duration, exists := ui.taskDayMap[task][day]; exists && duration > 0 {
+	ui.isEditing = true
+	if duration := cellDuration(entries); duration > 0 {
 		ui.editBuffer = datetimeutils.ShortDur(duration)
 	} else {
 		ui.editBuffer = ""
@@ -44,39 +59,21 @@ func (ui *ReportUI) saveEdit(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	}
 
-	task := ui.taskNames[ui.selectedCell.TaskIndex]
+	row := ui.rows[ui.selectedCell.TaskIndex]
+	task := row.Description
 	day := ui.days[ui.selectedCell.DayIndex]
+	entries := ui.cells[row][day]
 
-	currentTime := time.Now()
-	targetDate := time.Date(
-		ui.reportMonth.Year(),
-		ui.reportMonth.Month(),
-		day,
-		currentTime.Hour(),
-		currentTime.Minute(),
-		currentTime.Second(),
-		0,
-		time.UTC,
-	)
-
-	var existingID string
-	if ui.taskDayIDMap[task] != nil {
-		existingID = ui.taskDayIDMap[task][day]
-	}
-
-	if ui.taskDayMap[task] == nil {
-		ui.taskDayMap[task] = make(map[int]time.Duration)
-	}
-	ui.taskDayMap[task][day] = duration
+	ui.isEditing = false
+	ui.editBuffer = ""
 
 	// Edit existing time entry
-	if existingID != "" {
-		timeEntry := &clockify.TimeEntry{
-			Time:        targetDate,
-			Duration:    duration,
-			Description: task,
-			ProjectID:   ui.projectId,
-		}
+	if len(entries) == 1 {
+		existing := entries[0]
+		existingID := existing.ID
+		_, end := ui.editedInterval(&existing, duration)
+		timeEntry := ui.timeEntryFrom(&existing, end, duration)
+		start, _ := timeEntry.Interval()
 
 		ui.logInfo(
 			fmt.Sprintf(
@@ -84,125 +81,323 @@ func (ui *ReportUI) saveEdit(g *gocui.Gui, v *gocui.View) error {
 				existingID,
 				duration,
 				task,
-				targetDate.Format("2006-01-02"),
+				start.Format("2006-01-02 15:04"),
 			),
 		)
 
-		if err := ui.clockifyClient.EditLog(existingID, timeEntry); err != nil {
-			ui.logError(fmt.Sprintf("Failed to update time entry: %v", err))
-			ui.editBuffer = "Update failed"
+		ui.runRequest(g, func(ctx context.Context) error {
+			return ui.timeTracker.EditLog(ctx, existingID, timeEntry)
+		}, func(err error) error {
+			if err != nil {
+				ui.logError(fmt.Sprintf("Failed to update time entry: %s", describeError(err)))
+				return nil
+			}
+
+			existing.TimeInterval.Start, existing.TimeInterval.End = timeEntry.Interval()
+			ui.setCell(row, day, []tracker.ReportTimeEntry{existing})
+
+			logged := existing.Duration() // After rounding
+			ui.logInfo(fmt.Sprintf("Successfully updated %s for %s on day %d (ID: %s)", logged, task, day, existingID))
 			return nil
-		}
+		})
+		return nil
+	}
 
-		ui.logInfo(fmt.Sprintf("Successfully updated %s for %s on day %d (ID: %s)", duration, task, day, existingID))
-	} else {
-		timeEntry := &clockify.TimeEntry{
-			Time:        targetDate,
-			Duration:    duration,
-			Description: task,
-			ProjectID:   ui.projectId,
-		}
+	timeEntry := &tracker.TimeEntry{
+		Duration:    duration,
+		Description: task,
+		ProjectID:   row.ProjectID, // The project of the row, entries never move to another project
+		Rounding:    ui.config.Rounding.For(row.ProjectID),
+	}
+	timeEntry.Time, timeEntry.Exact = ui.newEntryEnd(day, duration)
+	start, _ := timeEntry.Interval()
 
-		ui.logInfo(fmt.Sprintf("Attempting to log new entry: %s for '%s' on %s", duration, task, targetDate.Format("2006-01-02")))
+	ui.logInfo(fmt.Sprintf("Attempting to log new entry: %s for '%s' on %s", duration, task, start.Format("2006-01-02 15:04")))
 
-		newEntryID, err := ui.clockifyClient.LogTime(timeEntry)
+	var newEntryID string
+	ui.runRequest(g, func(ctx context.Context) error {
+		var err error
+		newEntryID, err = ui.timeTracker.LogTime(ctx, timeEntry)
+		return err
+	}, func(err error) error {
 		if err != nil {
-			ui.logError(fmt.Sprintf("Failed to save time entry: %v", err))
-			ui.editBuffer = "Save failed"
+			ui.logError(fmt.Sprintf("Failed to save time entry: %s", describeError(err)))
 			return nil
 		}
 
-		if ui.taskDayIDMap[task] == nil {
-			ui.taskDayIDMap[task] = make(map[int]string)
-		}
-		ui.taskDayIDMap[task][day] = newEntryID
+		newEntry := tracker.ReportTimeEntry{ID: newEntryID, Description: task, ProjectID: row.ProjectID}
+		newEntry.TimeInterval.Start, newEntry.TimeInterval.End = timeEntry.Interval()
+		ui.setCell(row, day, []tracker.ReportTimeEntry{newEntry})
+
+		logged := newEntry.Duration() // After rounding
+		ui.logInfo(fmt.Sprintf("Successfully logged %s for %s on day %d (ID: %s)", logged, task, day, newEntryID))
+		return nil
+	})
+	return nil
+}
 
-		ui.logInfo(fmt.Sprintf("Successfully logged %s for %s on day %d (ID: %s)", duration, task, day, newEntryID))
+// editedInterval returns the interval of the entry changed to the duration, keeping its start or its end as configured.
+func (ui *ReportUI) editedInterval(entry *tracker.ReportTimeEntry, duration time.Duration) (time.Time, time.Time) {
+	if ui.config.KeepEnd && !entry.TimeInterval.End.IsZero() {
+		return entry.TimeInterval.End.Add(-duration), entry.TimeInterval.End
 	}
+	return entry.TimeInterval.Start, entry.TimeInterval.Start.Add(duration)
+}
 
-	ui.isEditing = false
-	ui.editBuffer = ""
-	return nil
+// newEntryEnd places a new entry on the day. With a workday start the entry follows the last entry of the day, so
+// that the entries do not overlap. Otherwise it ends at the current time of day rounded down like the entries logged
+// from the command line, which is reported by exact being false.
+func (ui *ReportUI) newEntryEnd(day int, duration time.Duration) (end time.Time, exact bool) {
+	shown := ui.period.date(day)
+	date := time.Date(shown.Year(), shown.Month(), shown.Day(), 0, 0, 0, 0, time.Local)
+
+	start, err := datetimeutils.AtClock(date, ui.config.WorkdayStart)
+	if ui.config.WorkdayStart == "" || err != nil {
+		now := time.Now()
+		return time.Date(date.Year(), date.Month(), date.Day(), now.Hour(), now.Minute(), 0, 0, time.Local), false
+	}
+
+	for _, days := range ui.cells {
+		for _, entry := range days[day] {
+			if entry.TimeInterval.End.After(start) {
+				start = entry.TimeInterval.End
+			}
+		}
+	}
+	return start.Add(duration), true
 }
 
+// timeEntryFrom creates the update of an existing entry ending at the given time, the attributes the report
+// does not show are kept as updates replace the whole entry. The configured rounding of the project applies.
+func (ui *ReportUI) timeEntryFrom(entry *tracker.ReportTimeEntry, end time.Time, duration time.Duration) *tracker.TimeEntry {
+	billable := entry.Billable
+	return &tracker.TimeEntry{
+		Time:        end,
+		Duration:    duration,
+		Description: entry.Description,
+		ProjectID:   entry.ProjectID, // Entries never move to another project
+		TaskID:      entry.TaskID,
+		TagIDs:      entry.TagIDs,
+		Billable:    &billable,
+		Exact:       true,
+		Rounding:    ui.config.Rounding.For(entry.ProjectID),
+	}
+}
+
+// deleteEntry deletes all entries of the selected cell once it is confirmed.
 func (ui *ReportUI) deleteEntry(g *gocui.Gui, v *gocui.View) error {
-	if ui.isEditing || ui.isAddingTask {
+	if ui.isEditing || ui.isAddingTask || len(ui.rows) == 0 || ui.isBusy() {
 		return nil
 	}
 
-	task := ui.taskNames[ui.selectedCell.TaskIndex]
+	row := ui.rows[ui.selectedCell.TaskIndex]
+	task := row.Description
 	day := ui.days[ui.selectedCell.DayIndex]
+	entries := ui.cells[row][day]
 
-	var existingID string
-	if ui.taskDayIDMap[task] != nil {
-		existingID = ui.taskDayIDMap[task][day]
-	}
-
-	if existingID == "" {
+	if len(entries) == 0 {
 		ui.logError("No time entry to delete at this position")
 		return nil
 	}
 
-	currentDuration := ui.taskDayMap[task][day]
-
-	ui.logInfo(fmt.Sprintf("Attempting to delete entry (ID %s): %s for '%s' on day %d",
-		existingID, datetimeutils.ShortDur(currentDuration), task, day))
+	total := datetimeutils.ShortDur(cellDuration(entries))
+	date := ui.period.date(day).Format("Mon Jan 2")
+	question := fmt.Sprintf("Delete %s of '%s' on %s?", total, truncateString(task, 20), date)
+	if len(entries) > 1 {
+		question = fmt.Sprintf("Delete all %d entries (%s) of '%s' on %s?", len(entries), total, truncateString(task, 20), date)
+	}
+	return ui.askConfirmation(g, question, func(g *gocui.Gui) error {
+		for _, entry := range entries {
+			ui.logInfo(fmt.Sprintf("Attempting to delete entry (ID %s): %s for '%s' on day %d",
+				entry.ID, datetimeutils.ShortDur(entry.Duration()), task, day))
+		}
 
-	if err := ui.clockifyClient.DeleteLog(existingID); err != nil {
-		ui.logError(fmt.Sprintf("Failed to delete time entry: %v", err))
+		errs := make([]error, len(entries)) // Of each entry
+		ui.runRequest(g, func(ctx context.Context) error {
+			for i, entry := range entries {
+				errs[i] = ui.timeTracker.DeleteLog(ctx, entry.ID)
+				if errors.Is(errs[i], context.Canceled) {
+					return errs[i]
+				}
+			}
+			return nil
+		}, func(error) error {
+			var remaining []tracker.ReportTimeEntry
+			for i, entry := range entries {
+				if errs[i] != nil {
+					ui.logError(fmt.Sprintf("Failed to delete time entry: %s", describeError(errs[i])))
+					remaining = append(remaining, entry)
+					continue
+				}
+				ui.logInfo(fmt.Sprintf("Successfully deleted %s for %s on day %d",
+					datetimeutils.ShortDur(entry.Duration()), task, day))
+			}
+			ui.setCell(row, day, remaining)
+			return nil
+		})
 		return nil
-	}
+	})
+}
 
-	delete(ui.taskDayMap[task], day)
-	delete(ui.taskDayIDMap[task], day)
+// setPeriodData shows the entries of the period, the selection stays on the same row and date when they are shown.
+func (ui *ReportUI) setPeriodData(p period, data []tracker.ReportTimeEntry) {
+	var selectedRow rowKey
+	if ui.selectedCell.TaskIndex < len(ui.rows) {
+		selectedRow = ui.rows[ui.selectedCell.TaskIndex]
+	}
+	selectedDate := ui.period.date(ui.days[ui.selectedCell.DayIndex])
 
-	ui.logInfo(fmt.Sprintf("Successfully deleted %s for %s on day %d",
-		datetimeutils.ShortDur(currentDuration), task, day))
+	ui.period = p
+	ui.days = p.days()
+	ui.setData(data)
 
-	return nil
+	ui.selectedCell = CellPosition{}
+	for i, row := range ui.rows {
+		if row == selectedRow {
+			ui.selectedCell.TaskIndex = i
+		}
+	}
+	for i, day := range ui.days {
+		if p.date(day).Equal(selectedDate) {
+			ui.selectedCell.DayIndex = i
+		}
+	}
 }
 
+// refreshData reloads the entries and the activity of the shown period.
 func (ui *ReportUI) refreshData(g *gocui.Gui, v *gocui.View) error {
-	// Don't refresh if we're in edit mode or adding a task
-	if ui.isEditing || ui.isAddingTask {
-		return nil
-	}
+	return ui.loadPeriod(g, ui.period)
+}
 
-	ui.logInfo("Refreshing data...")
+func (ui *ReportUI) previousPeriod(g *gocui.Gui, v *gocui.View) error {
+	return ui.loadPeriod(g, ui.period.previous())
+}
 
-	// Calculate the time range for the current report month
-	from := time.Date(ui.reportMonth.Year(), ui.reportMonth.Month(), 1, 0, 0, 0, 0, time.UTC)
-	to := from.AddDate(0, 1, 0).Add(-time.Second) // Last second of the month
+func (ui *ReportUI) nextPeriod(g *gocui.Gui, v *gocui.View) error {
+	return ui.loadPeriod(g, ui.period.next())
+}
 
-	// Fetch fresh data from Clockify
-	data, err := ui.clockifyClient.GetReport(from, to)
-	if err != nil {
-		ui.logError(fmt.Sprintf("Failed to refresh data: %v", err))
+// toggleWeekView switches between the month and the week of the selected day.
+func (ui *ReportUI) toggleWeekView(g *gocui.Gui, v *gocui.View) error {
+	if ui.isEditing {
 		return nil
 	}
-
-	// Update the UI data
-	ui.data = data
-	taskDayMap, taskNamesMap, taskDayIDMap := groupDataByTaskAndDay(data)
-	ui.taskDayMap = taskDayMap
-	ui.taskDayIDMap = taskDayIDMap
-
-	// Update task names
-	ui.taskNames = make([]string, 0, len(taskNamesMap))
-	for task := range taskNamesMap {
-		ui.taskNames = append(ui.taskNames, task)
+	selected := ui.period.date(ui.days[ui.selectedCell.DayIndex])
+	if ui.period.week {
+		return ui.loadPeriod(g, monthPeriod(selected))
 	}
-	sort.Strings(ui.taskNames)
+	return ui.loadPeriod(g, weekPeriod(selected))
+}
 
-	// Reset selected cell if it's out of bounds
-	if ui.selectedCell.TaskIndex >= len(ui.taskNames) {
-		ui.selectedCell.TaskIndex = 0
+// loadPeriod loads the entries and the activity of the period in the background so that the UI stays responsive and
+// the request can be cancelled with Esc or by quitting. The report switches to the period once its entries are
+// loaded, a failed or cancelled load keeps the current one.
+func (ui *ReportUI) loadPeriod(g *gocui.Gui, p period) error {
+	// Don't load if we're in edit mode, adding a task or looking at the entries of a cell
+	if ui.isEditing || ui.isAddingTask || ui.isShowingEntries || ui.isConfirming {
+		return nil
+	}
+	if ui.isBusy() {
+		return nil
 	}
-	if len(ui.days) > 0 && ui.selectedCell.DayIndex >= len(ui.days) {
-		ui.selectedCell.DayIndex = 0
+
+	if p == ui.period {
+		ui.logInfo("Refreshing data... (Esc to cancel)")
+	} else {
+		ui.logInfo(fmt.Sprintf("Loading %s... (Esc to cancel)", p))
 	}
 
-	ui.logInfo(fmt.Sprintf("Data refreshed successfully - found %d time entries", len(data)))
+	from, to := p.localRange()
+
+	ctx, cancel := context.WithCancel(ui.ctx)
+	ui.requestCancel = cancel
+
+	// An integration which is not configured keeps its error, it may be the error reading its secret
+	linearErr, gitlabErr := ui.linearErr, ui.gitlabErr
+
+	go func() {
+		var (
+			wg                 sync.WaitGroup
+			data               []tracker.ReportTimeEntry
+			err                error
+			linearLastActivity []linear.LastActivityItem
+			gitlabLastActivity []gitlab.LastActivityItem
+		)
+
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			data, err = ui.timeTracker.GetReport(ctx, from, to)
+		}()
+		go func() {
+			defer wg.Done()
+			if !ui.linearClient.IsConfigured() {
+				return
+			}
+			linearLastActivity, linearErr = ui.linearClient.GetLastActivity(ctx, from, to)
+		}()
+		go func() {
+			defer wg.Done()
+			if !ui.gitlabClient.IsConfigured() {
+				return
+			}
+			gitlabLastActivity, gitlabErr = ui.gitlabClient.GetLastActivity(ctx, from, to)
+		}()
+		wg.Wait()
+
+		g.Update(func(g *gocui.Gui) error {
+			cancel()
+			ui.requestCancel = nil
+
+			if errors.Is(err, context.Canceled) {
+				ui.logInfo("Refresh cancelled")
+				return nil
+			}
+			if err != nil {
+				ui.logError(fmt.Sprintf("Failed to refresh data: %s", describeError(err)))
+				return nil
+			}
+
+			ui.setPeriodData(p, data)
+			ui.linearLastActivity, ui.linearErr = linearLastActivity, linearErr
+			ui.gitlabLastActivity, ui.gitlabErr = gitlabLastActivity, gitlabErr
+
+			ui.logInfo(fmt.Sprintf("Data refreshed successfully - found %d time entries", len(data)))
+			return nil
+		})
+	}()
 
 	return nil
 }
+
+// runRequest runs the backend calls of a change in the background like loadPeriod, so that the UI stays responsive
+// and the calls can be cancelled with Esc or by quitting. done runs on the main loop with their result, unless they
+// were cancelled.
+func (ui *ReportUI) runRequest(g *gocui.Gui, request func(ctx context.Context) error, done func(err error) error) {
+	ctx, cancel := context.WithCancel(ui.ctx)
+	ui.requestCancel = cancel
+
+	go func() {
+		err := request(ctx)
+
+		g.Update(func(g *gocui.Gui) error {
+			cancel()
+			ui.requestCancel = nil
+
+			if errors.Is(err, context.Canceled) {
+				ui.logInfo("Request cancelled, refresh with Ctrl+R to see whether the change was saved")
+				return nil
+			}
+			return done(err)
+		})
+	}()
+}
+
+// isBusy reports whether a request is in progress, only one runs at a time.
+func (ui *ReportUI) isBusy() bool {
+	if ui.requestCancel == nil {
+		return false
+	}
+	ui.logInfo("A request is in progress, wait for it or cancel it with Esc")
+	return true
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/jroimartin/gocui"
+)
+
+const confirmPopupWidth = 60
+
+// askConfirmation shows the question in a popup, the action runs only when it is confirmed with y or Enter.
+func (ui *ReportUI) askConfirmation(g *gocui.Gui, question string, action func(g *gocui.Gui) error) error {
+	ui.isConfirming = true
+	ui.confirmQuestion = question
+	ui.confirmAction = action
+	ui.confirmReturnView = "table"
+	if v := g.CurrentView(); v != nil {
+		ui.confirmReturnView = v.Name()
+	}
+	return nil
+}
+
+// layoutConfirmPopup shows the pending question above the other views.
+func (ui *ReportUI) layoutConfirmPopup(g *gocui.Gui, maxX int, maxY int) error {
+	if !ui.isConfirming {
+		if err := g.DeleteView("confirmPopup"); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		return nil
+	}
+
+	x0 := (maxX - confirmPopupWidth) / 2
+	y0 := (maxY - 5) / 2
+	if v, err := g.SetView("confirmPopup", x0, y0, x0+confirmPopupWidth, y0+5); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = " Confirm "
+		v.Wrap = true
+
+		if _, err := g.SetCurrentView("confirmPopup"); err != nil {
+			return err
+		}
+	}
+
+	if v, err := g.View("confirmPopup"); err == nil {
+		v.Clear()
+		fmt.Fprintln(v, ui.confirmQuestion)
+		fmt.Fprint(v, "\ny/Enter: Yes | n/Esc: No")
+	}
+
+	return nil
+}
+
+func (ui *ReportUI) confirm(g *gocui.Gui, v *gocui.View) error {
+	action := ui.confirmAction
+	if err := ui.closeConfirmation(g); err != nil {
+		return err
+	}
+	return action(g)
+}
+
+func (ui *ReportUI) cancelConfirmation(g *gocui.Gui, v *gocui.View) error {
+	ui.logInfo("Cancelled")
+	return ui.closeConfirmation(g)
+}
+
+// closeConfirmation hides the popup and returns to the view the question was asked from.
+func (ui *ReportUI) closeConfirmation(g *gocui.Gui) error {
+	ui.isConfirming = false
+	ui.confirmQuestion = ""
+	ui.confirmAction = nil
+	if err := g.DeleteView("confirmPopup"); err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	if _, err := g.SetCurrentView(ui.confirmReturnView); err != nil {
+		return err
+	}
+	return nil
+}
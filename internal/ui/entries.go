@@ -0,0 +1,392 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/datetimeutils"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+	"github.com/jroimartin/gocui"
+)
+
+const entryPopupWidth = 80
+
+// selectedCellEntries returns the row, the day and the entries of the selected cell.
+func (ui *ReportUI) selectedCellEntries() (rowKey, int, []tracker.ReportTimeEntry) {
+	row := ui.rows[ui.selectedCell.TaskIndex]
+	day := ui.days[ui.selectedCell.DayIndex]
+	return row, day, ui.cells[row][day]
+}
+
+// showEntries opens the popup listing every entry of the selected cell.
+func (ui *ReportUI) showEntries(g *gocui.Gui, v *gocui.View) error {
+	if ui.isEditing || ui.isAddingTask || ui.isShowingEntries || len(ui.rows) == 0 {
+		return nil
+	}
+	_, _, entries := ui.selectedCellEntries()
+	if len(entries) == 0 {
+		ui.logError("No time entry at this position")
+		return nil
+	}
+	ui.loadEntryLabels(g, entries)
+
+	ui.isShowingEntries = true
+	ui.selectedEntry = 0
+	ui.isEditingEntry = false
+	ui.entryEditBuffer = ""
+	return nil
+}
+
+func (ui *ReportUI) layoutEntryPopup(g *gocui.Gui, maxX int, maxY int) error {
+	if !ui.isShowingEntries {
+		if err := g.DeleteView("entryPopup"); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		return nil
+	}
+
+	row, day, entries := ui.selectedCellEntries()
+
+	popupHeight := len(entries) + 7
+	x0 := (maxX - entryPopupWidth) / 2
+	y0 := (maxY - popupHeight) / 2
+	x1 := x0 + entryPopupWidth
+	y1 := y0 + popupHeight
+
+	if v, err := g.SetView("entryPopup", x0, y0, x1, y1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Wrap = true
+
+		if _, err := g.SetCurrentView("entryPopup"); err != nil {
+			return err
+		}
+	}
+
+	if v, err := g.View("entryPopup"); err == nil {
+		date := ui.period.date(day)
+		v.Title = fmt.Sprintf(" %s - %s ", truncateString(row.Description, entryPopupWidth-20), date.Format("Mon Jan 2"))
+		v.Clear()
+
+		fmt.Fprintf(v, "  %-8s%-8s%-10s%-8s%s\n", "Start", "End", "Duration", "Status", "Details")
+		for i, entry := range entries {
+			marker := "  "
+			if i == ui.selectedEntry {
+				marker = "> "
+			}
+
+			end := "running"
+			if !entry.TimeInterval.End.IsZero() {
+				end = entry.TimeInterval.End.Local().Format("15:04")
+			}
+
+			duration := datetimeutils.ShortDur(entry.Duration())
+			if ui.isEditingEntry && i == ui.selectedEntry {
+				duration = "[" + ui.entryEditBuffer + "]"
+			}
+
+			status := ""
+			if entry.IsLocked {
+				status = "locked"
+			}
+
+			fmt.Fprintf(v, "%s%-8s%-8s%-10s%-8s%s\n", marker, entry.TimeInterval.Start.Local().Format("15:04"), end, duration, status,
+				truncateString(ui.entryDetails(entry), entryPopupWidth-38))
+		}
+
+		fmt.Fprintf(v, "\nTotal: %s\n", datetimeutils.ShortDur(cellDuration(entries)))
+		fmt.Fprint(v, "Enter: Edit/Save | Ctrl+D: Delete | M: Merge into one | Esc: Close")
+	}
+
+	return nil
+}
+
+func (ui *ReportUI) closeEntries(g *gocui.Gui, v *gocui.View) error {
+	if ui.requestCancel != nil && !ui.isEditingEntry {
+		ui.requestCancel()
+		return nil
+	}
+	if ui.isEditingEntry {
+		ui.isEditingEntry = false
+		ui.entryEditBuffer = ""
+		ui.logInfo("Edit cancelled")
+		return nil
+	}
+
+	ui.isShowingEntries = false
+	if _, err := g.SetCurrentView("table"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ui *ReportUI) moveEntryUp(g *gocui.Gui, v *gocui.View) error {
+	if !ui.isEditingEntry && ui.selectedEntry > 0 {
+		ui.selectedEntry--
+	}
+	return nil
+}
+
+func (ui *ReportUI) moveEntryDown(g *gocui.Gui, v *gocui.View) error {
+	_, _, entries := ui.selectedCellEntries()
+	if !ui.isEditingEntry && ui.selectedEntry < len(entries)-1 {
+		ui.selectedEntry++
+	}
+	return nil
+}
+
+func (ui *ReportUI) makeEntryCharHandler(ch rune) func(*gocui.Gui, *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if ui.isEditingEntry {
+			ui.entryEditBuffer += string(ch)
+		}
+		return nil
+	}
+}
+
+func (ui *ReportUI) handleEntryBackspace(g *gocui.Gui, v *gocui.View) error {
+	if ui.isEditingEntry && len(ui.entryEditBuffer) > 0 {
+		ui.entryEditBuffer = ui.entryEditBuffer[:len(ui.entryEditBuffer)-1]
+	}
+	return nil
+}
+
+func (ui *ReportUI) editEntry(g *gocui.Gui, v *gocui.View) error {
+	if ui.isEditingEntry {
+		return ui.saveEntryEdit(g, v)
+	}
+	if ui.isBusy() {
+		return nil
+	}
+
+	_, _, entries := ui.selectedCellEntries()
+	entry := entries[ui.selectedEntry]
+	ui.isEditingEntry = true
+	ui.entryEditBuffer = datetimeutils.ShortDur(entry.Duration())
+	return nil
+}
+
+// saveEntryEdit changes the duration of the selected entry, keeping its start or its end as configured.
+func (ui *ReportUI) saveEntryEdit(g *gocui.Gui, v *gocui.View) error {
+	buffer := ui.entryEditBuffer
+	ui.isEditingEntry = false
+	ui.entryEditBuffer = ""
+	if buffer == "" {
+		return nil
+	}
+
+	duration, err := time.ParseDuration(buffer)
+	if err != nil {
+		ui.logError(fmt.Sprintf("Invalid duration format: %s", buffer))
+		return nil
+	}
+
+	row, day, entries := ui.selectedCellEntries()
+	entry := entries[ui.selectedEntry]
+	_, end := ui.editedInterval(&entry, duration)
+	timeEntry := ui.timeEntryFrom(&entry, end, duration)
+
+	selected := ui.selectedEntry
+
+	ui.logInfo(fmt.Sprintf("Attempting to update existing entry (ID %s): %s for '%s' on day %d", entry.ID, duration, row.Description, day))
+
+	ui.runRequest(g, func(ctx context.Context) error {
+		return ui.timeTracker.EditLog(ctx, entry.ID, timeEntry)
+	}, func(err error) error {
+		if err != nil {
+			ui.logError(fmt.Sprintf("Failed to update time entry: %s", describeError(err)))
+			return nil
+		}
+
+		updated := append([]tracker.ReportTimeEntry(nil), entries...)
+		updated[selected].TimeInterval.Start, updated[selected].TimeInterval.End = timeEntry.Interval()
+		ui.setCell(row, day, updated)
+
+		logged := updated[selected].Duration() // After rounding
+		ui.logInfo(fmt.Sprintf("Successfully updated %s for %s on day %d (ID: %s)", logged, row.Description, day, entry.ID))
+		return nil
+	})
+	return nil
+}
+
+// deleteSelectedEntry deletes the selected entry once it is confirmed.
+func (ui *ReportUI) deleteSelectedEntry(g *gocui.Gui, v *gocui.View) error {
+	if ui.isEditingEntry || ui.isBusy() {
+		return nil
+	}
+
+	row, day, entries := ui.selectedCellEntries()
+	selected := ui.selectedEntry
+	entry := entries[selected]
+	duration := datetimeutils.ShortDur(entry.Duration())
+
+	question := fmt.Sprintf("Delete the entry of %s starting at %s for '%s'?",
+		duration, entry.TimeInterval.Start.Local().Format("15:04"), truncateString(row.Description, 20))
+	return ui.askConfirmation(g, question, func(g *gocui.Gui) error {
+		ui.logInfo(fmt.Sprintf("Attempting to delete entry (ID %s): %s for '%s' on day %d", entry.ID, duration, row.Description, day))
+
+		ui.runRequest(g, func(ctx context.Context) error {
+			return ui.timeTracker.DeleteLog(ctx, entry.ID)
+		}, func(err error) error {
+			if err != nil {
+				ui.logError(fmt.Sprintf("Failed to delete time entry: %s", describeError(err)))
+				return nil
+			}
+
+			remaining := append([]tracker.ReportTimeEntry(nil), entries[:selected]...)
+			remaining = append(remaining, entries[selected+1:]...)
+			ui.setCell(row, day, remaining)
+
+			ui.logInfo(fmt.Sprintf("Successfully deleted %s for %s on day %d", duration, row.Description, day))
+
+			if len(remaining) == 0 {
+				return ui.closeEntries(g, nil)
+			}
+			if ui.selectedEntry >= len(remaining) {
+				ui.selectedEntry = len(remaining) - 1
+			}
+			return nil
+		})
+		return nil
+	})
+}
+
+// mergeEntries replaces the entries of the cell with a single one once it is confirmed. The first entry is extended
+// to the total duration of the cell and the others are deleted. Locked and running entries cannot be merged.
+func (ui *ReportUI) mergeEntries(g *gocui.Gui, v *gocui.View) error {
+	if ui.isEditingEntry || ui.isBusy() {
+		return nil
+	}
+
+	row, day, entries := ui.selectedCellEntries()
+	if len(entries) < 2 {
+		ui.logError("Nothing to merge, the cell has a single entry")
+		return nil
+	}
+	for _, entry := range entries {
+		start := entry.TimeInterval.Start.Local().Format("15:04")
+		if entry.IsLocked {
+			ui.logError(fmt.Sprintf("Cannot merge, the entry starting at %s is locked", start))
+			return nil
+		}
+		if entry.TimeInterval.End.IsZero() {
+			ui.logError(fmt.Sprintf("Cannot merge, the entry starting at %s is still running", start))
+			return nil
+		}
+	}
+
+	total := cellDuration(entries)
+	question := fmt.Sprintf("Merge the %d entries of '%s' into one of %s?", len(entries), truncateString(row.Description, 20), datetimeutils.ShortDur(total))
+	return ui.askConfirmation(g, question, func(g *gocui.Gui) error {
+		first := entries[0]
+		end := first.TimeInterval.Start.Add(total)
+
+		ui.logInfo(fmt.Sprintf("Attempting to merge %d entries of '%s' on day %d into one of %s", len(entries), row.Description, day, total))
+
+		timeEntry := ui.timeEntryFrom(&first, end, total)
+		deleteErrs := make([]error, len(entries)-1) // Of the entries after the first one
+		ui.runRequest(g, func(ctx context.Context) error {
+			if err := ui.timeTracker.EditLog(ctx, first.ID, timeEntry); err != nil {
+				return err
+			}
+			for i, entry := range entries[1:] {
+				deleteErrs[i] = ui.timeTracker.DeleteLog(ctx, entry.ID)
+				if errors.Is(deleteErrs[i], context.Canceled) {
+					return deleteErrs[i]
+				}
+			}
+			return nil
+		}, func(err error) error {
+			if err != nil {
+				ui.logError(fmt.Sprintf("Failed to update time entry: %s", describeError(err)))
+				return nil
+			}
+			first.TimeInterval.Start, first.TimeInterval.End = timeEntry.Interval()
+
+			remaining := []tracker.ReportTimeEntry{first}
+			for i, entry := range entries[1:] {
+				if err := deleteErrs[i]; err != nil {
+					ui.logError(fmt.Sprintf("Failed to delete time entry %s, the cell now counts it twice: %s", entry.ID, describeError(err)))
+					remaining = append(remaining, entry)
+				}
+			}
+			ui.setCell(row, day, remaining)
+			ui.selectedEntry = 0
+
+			if len(remaining) == 1 {
+				ui.logInfo(fmt.Sprintf("Successfully merged the entries of %s on day %d into one of %s", row.Description, day, total))
+			}
+			return nil
+		})
+		return nil
+	})
+}
+
+// loadEntryLabels loads the names of the tasks and tags of the entries in the background, the tasks once per project.
+// Until they are loaded, or when the backend cannot list them, the popup leaves them out.
+func (ui *ReportUI) loadEntryLabels(g *gocui.Gui, entries []tracker.ReportTimeEntry) {
+	for _, entry := range entries {
+		taskLister, ok := ui.timeTracker.(tracker.TaskLister)
+		if ok && entry.TaskID != "" && !ui.taskProjects[entry.ProjectID] {
+			ui.taskProjects[entry.ProjectID] = true
+			projectID := entry.ProjectID
+			go func() {
+				tasks, err := taskLister.ListTasks(ui.ctx, projectID)
+				g.Update(func(g *gocui.Gui) error {
+					if err != nil {
+						ui.logError(fmt.Sprintf("Failed to load the tasks of %s: %s", ui.projectName(projectID), describeError(err)))
+						return nil
+					}
+					for _, task := range tasks {
+						ui.taskNames[task.ID] = task.Name
+					}
+					return nil
+				})
+			}()
+		}
+
+		tagLister, ok := ui.timeTracker.(tracker.TagLister)
+		if ok && len(entry.TagIDs) > 0 && !ui.tagsRequested {
+			ui.tagsRequested = true
+			go func() {
+				tags, err := tagLister.ListTags(ui.ctx)
+				g.Update(func(g *gocui.Gui) error {
+					if err != nil {
+						ui.logError(fmt.Sprintf("Failed to load the tags: %s", describeError(err)))
+						return nil
+					}
+					for _, tag := range tags {
+						ui.tagNames[tag.ID] = tag.Name
+					}
+					return nil
+				})
+			}()
+		}
+	}
+}
+
+// entryDetails describes the task, tags and billable flag of the entry, a task or tag is left out until its name is
+// known.
+func (ui *ReportUI) entryDetails(entry tracker.ReportTimeEntry) string {
+	var details []string
+	if name, ok := ui.taskNames[entry.TaskID]; ok {
+		details = append(details, "task "+name)
+	}
+	var tags []string
+	for _, ID := range entry.TagIDs {
+		if name, ok := ui.tagNames[ID]; ok {
+			tags = append(tags, name)
+		}
+	}
+	if len(tags) > 0 {
+		details = append(details, "tags "+strings.Join(tags, ", "))
+	}
+	if entry.Billable {
+		details = append(details, "billable")
+	}
+	return strings.Join(details, "; ")
+}
@@ -3,7 +3,7 @@ package ui
 import "github.com/jroimartin/gocui"
 
 func (ui *ReportUI) setKeybindings(g *gocui.Gui) error {
-	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, ui.quit); err != nil {
 		return err
 	}
 
@@ -99,11 +99,29 @@ func (ui *ReportUI) setKeybindings(g *gocui.Gui) error {
 		return err
 	}
 
+	// Add keybinding to show the entries of a cell with Ctrl+E
+	if err := g.SetKeybinding("table", gocui.KeyCtrlE, gocui.ModNone, ui.showEntries); err != nil {
+		return err
+	}
+
 	// Add keybinding to refresh data with Ctrl+R
 	if err := g.SetKeybinding("", gocui.KeyCtrlR, gocui.ModNone, ui.refreshData); err != nil {
 		return err
 	}
 
+	// Switch to the previous or next month or week with [ and ], w or W toggles the week view
+	if err := g.SetKeybinding("table", '[', gocui.ModNone, ui.previousPeriod); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("table", ']', gocui.ModNone, ui.nextPeriod); err != nil {
+		return err
+	}
+	for _, ch := range []rune{'w', 'W'} {
+		if err := g.SetKeybinding("table", ch, gocui.ModNone, ui.toggleWeekView); err != nil {
+			return err
+		}
+	}
+
 	// Keybindings for task popup
 	if err := g.SetKeybinding("taskPopup", gocui.KeyEnter, gocui.ModNone, ui.confirmNewTask); err != nil {
 		return err
@@ -112,6 +130,55 @@ func (ui *ReportUI) setKeybindings(g *gocui.Gui) error {
 		return err
 	}
 
+	// Keybindings for the popup with the entries of a cell
+	if err := g.SetKeybinding("entryPopup", gocui.KeyArrowUp, gocui.ModNone, ui.moveEntryUp); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("entryPopup", gocui.KeyArrowDown, gocui.ModNone, ui.moveEntryDown); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("entryPopup", gocui.KeyEnter, gocui.ModNone, ui.editEntry); err != nil {
+		return err
+	}
+	_ = g.SetKeybinding("entryPopup", gocui.KeyEsc, gocui.ModNone, ui.closeEntries)
+	if err := g.SetKeybinding("entryPopup", 'q', gocui.ModNone, ui.closeEntries); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("entryPopup", gocui.KeyCtrlD, gocui.ModNone, ui.deleteSelectedEntry); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("entryPopup", gocui.KeyBackspace, gocui.ModNone, ui.handleEntryBackspace); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("entryPopup", gocui.KeyBackspace2, gocui.ModNone, ui.handleEntryBackspace); err != nil {
+		return err
+	}
+	for _, ch := range chars {
+		if err := g.SetKeybinding("entryPopup", ch, gocui.ModNone, ui.makeEntryCharHandler(ch)); err != nil {
+			return err
+		}
+	}
+	// Only the upper case M merges, the lower case m is a duration character
+	if err := g.SetKeybinding("entryPopup", 'M', gocui.ModNone, ui.mergeEntries); err != nil {
+		return err
+	}
+
+	// Keybindings for the confirmation popup
+	for _, ch := range []rune{'y', 'Y'} {
+		if err := g.SetKeybinding("confirmPopup", ch, gocui.ModNone, ui.confirm); err != nil {
+			return err
+		}
+	}
+	if err := g.SetKeybinding("confirmPopup", gocui.KeyEnter, gocui.ModNone, ui.confirm); err != nil {
+		return err
+	}
+	for _, ch := range []rune{'n', 'N', 'q'} {
+		if err := g.SetKeybinding("confirmPopup", ch, gocui.ModNone, ui.cancelConfirmation); err != nil {
+			return err
+		}
+	}
+	_ = g.SetKeybinding("confirmPopup", gocui.KeyEsc, gocui.ModNone, ui.cancelConfirmation)
+
 	return nil
 }
 
@@ -129,7 +196,7 @@ func (ui *ReportUI) moveCursorDown(g *gocui.Gui, v *gocui.View) error {
 	if ui.isEditing || ui.isAddingTask {
 		return nil
 	}
-	if ui.selectedCell.TaskIndex < len(ui.taskNames)-1 {
+	if ui.selectedCell.TaskIndex < len(ui.rows)-1 {
 		ui.selectedCell.TaskIndex++
 	}
 	return nil
@@ -156,6 +223,10 @@ func (ui *ReportUI) moveCursorRight(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (ui *ReportUI) cancelEdit(g *gocui.Gui, v *gocui.View) error {
+	if ui.requestCancel != nil && !ui.isEditing && !ui.isAddingTask {
+		ui.requestCancel()
+		return nil
+	}
 	if ui.isAddingTask {
 		ui.isAddingTask = false
 		ui.newTaskBuffer = ""
@@ -214,7 +285,7 @@ func (ui *ReportUI) scrollLogDown(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (ui *ReportUI) focusTable(g *gocui.Gui, v *gocui.View) error {
-	if !ui.isEditing && !ui.isAddingTask {
+	if !ui.isEditing && !ui.isAddingTask && !ui.isShowingEntries {
 		_, err := g.SetCurrentView("table")
 		return err
 	}
@@ -222,7 +293,7 @@ func (ui *ReportUI) focusTable(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (ui *ReportUI) focusLinearActivity(g *gocui.Gui, v *gocui.View) error {
-	if !ui.isEditing && !ui.isAddingTask {
+	if !ui.isEditing && !ui.isAddingTask && !ui.isShowingEntries {
 		_, err := g.SetCurrentView("linearActivity")
 		return err
 	}
@@ -230,7 +301,7 @@ func (ui *ReportUI) focusLinearActivity(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (ui *ReportUI) focusGitActivity(g *gocui.Gui, v *gocui.View) error {
-	if !ui.isEditing && !ui.isAddingTask {
+	if !ui.isEditing && !ui.isAddingTask && !ui.isShowingEntries {
 		_, err := g.SetCurrentView("gitActivity")
 		return err
 	}
@@ -273,12 +344,18 @@ func (ui *ReportUI) makeCharHandler(ch rune) func(*gocui.Gui, *gocui.View) error
 }
 
 func (ui *ReportUI) addNewTask(g *gocui.Gui, v *gocui.View) error {
-	if ui.isEditing || ui.isAddingTask {
+	if ui.isEditing || ui.isAddingTask || ui.isShowingEntries || ui.isConfirming {
 		return nil // Don't allow if already in edit mode
 	}
 
 	ui.isAddingTask = true
 	ui.newTaskBuffer = ""
+	ui.newTaskProject = 0
+	for i, project := range ui.projectChoices() {
+		if project.ID == ui.projectId {
+			ui.newTaskProject = i
+		}
+	}
 	ui.logInfo("Enter new task name (press Enter to confirm, Esc to cancel)")
 	return nil
 }
@@ -294,7 +371,9 @@ func (ui *ReportUI) cancelAddTask(g *gocui.Gui, v *gocui.View) error {
 	return nil
 }
 
-func quit(g *gocui.Gui, v *gocui.View) error {
+// quit exits the UI and cancels all in-flight requests.
+func (ui *ReportUI) quit(g *gocui.Gui, v *gocui.View) error {
+	ui.cancel()
 	return gocui.ErrQuit
 }
 
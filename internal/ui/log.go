@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/andrejsoucek/chronos/pkg/apierror"
 	"github.com/jroimartin/gocui"
 )
 
@@ -14,6 +16,29 @@ func (ui *ReportUI) logError(message string) {
 	ui.shouldAutoScroll = true
 }
 
+// describeError explains failed API requests in terms of what the user can do about them.
+func describeError(err error) string {
+	var apiErr *apierror.APIError
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+
+	switch {
+	case apierror.IsLocked(err):
+		return "entry is locked by approval"
+	case apierror.IsUnauthorized(err):
+		return fmt.Sprintf("%s rejected the credentials, check the API key", apiErr.Service)
+	case apierror.IsNotFound(err):
+		return "entry no longer exists, refresh the report with Ctrl+R"
+	case apierror.IsRateLimited(err):
+		return fmt.Sprintf("%s rate limit exceeded, try again later", apiErr.Service)
+	case apierror.IsValidation(err):
+		return fmt.Sprintf("%s rejected the entry: %s", apiErr.Service, apiErr.Message)
+	default:
+		return err.Error()
+	}
+}
+
 func (ui *ReportUI) logInfo(message string) {
 	timestamp := time.Now().Format("15:04:05")
 	logEntry := fmt.Sprintf("[%s] INFO: %s", timestamp, message)
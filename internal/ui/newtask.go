@@ -2,11 +2,14 @@ package ui
 
 import (
 	"fmt"
-	"time"
 
+	"github.com/andrejsoucek/chronos/pkg/tracker"
 	"github.com/jroimartin/gocui"
 )
 
+// Number of projects visible at once in the add task popup
+const projectListHeight = 6
+
 type taskEditor struct {
 	ui *ReportUI
 }
@@ -17,6 +20,14 @@ func (e *taskEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modif
 		return
 	case key == gocui.KeyEsc:
 		return
+	case key == gocui.KeyArrowUp:
+		if e.ui.newTaskProject > 0 {
+			e.ui.newTaskProject--
+		}
+	case key == gocui.KeyArrowDown:
+		if e.ui.newTaskProject < len(e.ui.projectChoices())-1 {
+			e.ui.newTaskProject++
+		}
 	case key == gocui.KeyBackspace || key == gocui.KeyBackspace2:
 		if len(e.ui.newTaskBuffer) > 0 {
 			e.ui.newTaskBuffer = e.ui.newTaskBuffer[:len(e.ui.newTaskBuffer)-1]
@@ -28,15 +39,85 @@ func (e *taskEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modif
 	}
 }
 
+// projectChoices returns the projects a new task can be added to, the default project is always among them.
+func (ui *ReportUI) projectChoices() []tracker.Project {
+	for _, project := range ui.projects {
+		if project.ID == ui.projectId {
+			return ui.projects
+		}
+	}
+	return append([]tracker.Project{{ID: ui.projectId, Name: ui.projectName(ui.projectId)}}, ui.projects...)
+}
+
+// layoutTaskPopup shows the popup for adding a task with the list of projects to pick from.
+func (ui *ReportUI) layoutTaskPopup(g *gocui.Gui, maxX int, maxY int) error {
+	if !ui.isAddingTask {
+		if err := g.DeleteView("taskPopup"); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		return nil
+	}
+
+	choices := ui.projectChoices()
+	listHeight := min(len(choices), projectListHeight)
+
+	popupWidth := 50
+	popupHeight := listHeight + 6
+	x0 := (maxX - popupWidth) / 2
+	y0 := (maxY - popupHeight) / 2
+	x1 := x0 + popupWidth
+	y1 := y0 + popupHeight
+
+	if v, err := g.SetView("taskPopup", x0, y0, x1, y1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = " Add New Task "
+		v.Wrap = true
+		v.Editable = true
+		v.Editor = &taskEditor{ui: ui}
+
+		if _, err := g.SetCurrentView("taskPopup"); err != nil {
+			return err
+		}
+	}
+
+	if v, err := g.View("taskPopup"); err == nil {
+		v.Clear()
+		fmt.Fprintf(v, "Task name: %s\n\nProject (Up/Down to change):\n", ui.newTaskBuffer)
+
+		// Scroll the list so that the selected project is visible
+		first := 0
+		if ui.newTaskProject >= listHeight {
+			first = ui.newTaskProject - listHeight + 1
+		}
+		for i := first; i < first+listHeight; i++ {
+			marker := "  "
+			if i == ui.newTaskProject {
+				marker = "> "
+			}
+			fmt.Fprintln(v, marker+truncateString(choices[i].Name, popupWidth-4))
+		}
+
+		fmt.Fprint(v, "\nPress Enter to confirm, Esc to cancel")
+		v.SetCursor(len(ui.newTaskBuffer), 0)
+	}
+
+	return nil
+}
+
 func (ui *ReportUI) confirmNewTask(g *gocui.Gui, v *gocui.View) error {
 	if ui.newTaskBuffer == "" {
 		ui.logError("Task name cannot be empty")
 		return nil
 	}
 
-	for _, existingTask := range ui.taskNames {
-		if existingTask == ui.newTaskBuffer {
-			ui.logError(fmt.Sprintf("Task '%s' already exists", ui.newTaskBuffer))
+	project := ui.projectChoices()[ui.newTaskProject]
+	newRow := rowKey{ProjectID: project.ID, Description: ui.newTaskBuffer}
+
+	for _, existingRow := range ui.rows {
+		if existingRow == newRow {
+			ui.logError(fmt.Sprintf("Task '%s' already exists in project %s", ui.newTaskBuffer, project.Name))
 			ui.isAddingTask = false
 			ui.newTaskBuffer = ""
 			if _, err := g.SetCurrentView("table"); err != nil {
@@ -46,19 +127,21 @@ func (ui *ReportUI) confirmNewTask(g *gocui.Gui, v *gocui.View) error {
 		}
 	}
 
-	ui.taskNames = append(ui.taskNames, ui.newTaskBuffer)
+	ui.rows = append(ui.rows, newRow)
+	ui.sortRows()
 
-	if ui.taskDayMap[ui.newTaskBuffer] == nil {
-		ui.taskDayMap[ui.newTaskBuffer] = make(map[int]time.Duration)
-	}
-	if ui.taskDayIDMap[ui.newTaskBuffer] == nil {
-		ui.taskDayIDMap[ui.newTaskBuffer] = make(map[int]string)
+	if ui.cells[newRow] == nil {
+		ui.cells[newRow] = make(map[int][]tracker.ReportTimeEntry)
 	}
 
-	ui.selectedCell.TaskIndex = len(ui.taskNames) - 1
+	for i, row := range ui.rows {
+		if row == newRow {
+			ui.selectedCell.TaskIndex = i
+		}
+	}
 	ui.selectedCell.DayIndex = 0
 
-	ui.logInfo(fmt.Sprintf("Added new task: '%s'", ui.newTaskBuffer))
+	ui.logInfo(fmt.Sprintf("Added new task: '%s' in project %s", ui.newTaskBuffer, project.Name))
 
 	ui.isAddingTask = false
 	ui.newTaskBuffer = ""
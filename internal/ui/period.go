@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/datetimeutils"
+)
+
+// period is the range of days shown in the report, a whole month or a week from Monday to Sunday. The cells are
+// keyed by the day of the month, which is unique within a week as well.
+type period struct {
+	start time.Time // First day at midnight UTC
+	week  bool
+}
+
+func monthPeriod(t time.Time) period {
+	return period{start: time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func weekPeriod(t time.Time) period {
+	monday, _ := datetimeutils.WeekRange(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+	return period{start: monday, week: true}
+}
+
+// days returns the days of the month shown in the period.
+func (p period) days() []int {
+	if !p.week {
+		return datetimeutils.DaysInMonth(p.start)
+	}
+	days := make([]int, 7)
+	for i := range days {
+		days[i] = p.start.AddDate(0, 0, i).Day()
+	}
+	return days
+}
+
+// date returns the date of the day of the month shown in the period.
+func (p period) date(day int) time.Time {
+	if !p.week {
+		return time.Date(p.start.Year(), p.start.Month(), day, 0, 0, 0, 0, time.UTC)
+	}
+	for i := range 7 {
+		if date := p.start.AddDate(0, 0, i); date.Day() == day {
+			return date
+		}
+	}
+	return p.start
+}
+
+func (p period) previous() period {
+	if p.week {
+		return period{start: p.start.AddDate(0, 0, -7), week: true}
+	}
+	return period{start: p.start.AddDate(0, -1, 0)}
+}
+
+func (p period) next() period {
+	if p.week {
+		return period{start: p.start.AddDate(0, 0, 7), week: true}
+	}
+	return period{start: p.start.AddDate(0, 1, 0)}
+}
+
+// localRange returns the first and the last second of the period in the local time zone, the entries are grouped
+// by their local day.
+func (p period) localRange() (time.Time, time.Time) {
+	start := time.Date(p.start.Year(), p.start.Month(), p.start.Day(), 0, 0, 0, 0, time.Local)
+	if p.week {
+		return datetimeutils.WeekRange(start)
+	}
+	return datetimeutils.MonthRange(start)
+}
+
+func (p period) String() string {
+	if !p.week {
+		return p.start.Format("January 2006")
+	}
+	_, week := p.start.ISOWeek()
+	end := p.start.AddDate(0, 0, 6)
+	return fmt.Sprintf("Week %d, %s - %s", week, p.start.Format("Jan 2"), end.Format("Jan 2 2006"))
+}
@@ -1,27 +1,62 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/andrejsoucek/chronos/pkg/clockify"
 	"github.com/andrejsoucek/chronos/pkg/datetimeutils"
 	"github.com/andrejsoucek/chronos/pkg/gitlab"
+	"github.com/andrejsoucek/chronos/pkg/holidays"
+	"github.com/andrejsoucek/chronos/pkg/httpretry"
 	"github.com/andrejsoucek/chronos/pkg/linear"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
 	"github.com/jroimartin/gocui"
 )
 
 const (
 	taskColumnWidth    = 35
 	dayColumnWidth     = 8
+	weekColumnWidth    = 21 // Fits the duration with the start and end of the cell
 	ellipsisLength     = 3
 	asciiEsc           = 27
 	weekendPlaceholder = "x" // Used to visually differentiate weekends when empty
+	holidayPlaceholder = "H" // Public holidays when empty
+	leavePlaceholder   = "L" // Leave days when empty
+	colorRed           = "\033[31m"
+	colorGreen         = "\033[32m"
+	colorReset         = "\033[0m"
 )
 
+// rowKey identifies a row of the report, the entries are grouped by their project and description.
+type rowKey struct {
+	ProjectID   string
+	Description string
+}
+
+// ReportConfig controls where edited and new entries are placed on their day and the initial view.
+type ReportConfig struct {
+	Week         bool   // Start in the week view instead of the month view
+	KeepEnd      bool   // Changing the duration of an entry moves its start instead of its end
+	WorkdayStart string // HH:MM, new entries of a day follow each other from this time instead of ending now
+	Rounding     *tracker.RoundingPolicy
+	Schedule     *tracker.Schedule  // Targets the totals are compared with, nil hides the balance
+	Calendar     *holidays.Calendar // Holidays and leave shown like weekends, nil when there are none
+}
+
+// tableState is what the rendered table depends on, the table is rebuilt only when it changes.
+type tableState struct {
+	width      int
+	selected   CellPosition
+	isEditing  bool
+	editBuffer string
+	version    int
+	minute     int64 // Running timers and the due days change with the time
+}
+
 type CellPosition struct {
 	TaskIndex int
 	DayIndex  int
@@ -29,34 +64,70 @@ type CellPosition struct {
 }
 
 type ReportUI struct {
-	clockifyClient     *clockify.Clockify
+	ctx                context.Context
+	cancel             context.CancelFunc
+	requestCancel      context.CancelFunc // Cancels the in-flight request, nil when no request is running
+	timeTracker        tracker.TimeTracker
+	linearClient       *linear.Linear
+	gitlabClient       *gitlab.Gitlab
 	linearLastActivity []linear.LastActivityItem
+	linearErr          error
 	gitlabLastActivity []gitlab.LastActivityItem
-	data               []clockify.ReportTimeEntry
-	reportMonth        time.Time
-	taskDayMap         map[string]map[int]time.Duration
-	taskDayIDMap       map[string]map[int]string // Maps task+day to time entry ID for existing entries
-	taskNames          []string
+	gitlabErr          error
+	data               []tracker.ReportTimeEntry
+	period             period
+	cells              map[rowKey]map[int][]tracker.ReportTimeEntry // All entries of a row and day, sorted by start
+	rows               []rowKey                                     // Sorted by project name, the rows of a project are adjacent
+	projects           []tracker.Project
+	projectNames       map[string]string
+	taskNames          map[string]string // By task ID, loaded in the background when the entries popup shows a task
+	tagNames           map[string]string // By tag ID, loaded in the background when the entries popup shows a tag
+	taskProjects       map[string]bool   // Projects whose tasks are loaded or being loaded
+	tagsRequested      bool
 	days               []int
+	firstDay           int // Index of the first visible day, the days scroll horizontally to follow the selected cell
+	tableVersion       int // Incremented on every change of the rows or cells
+	renderedTable      tableState
 	selectedCell       CellPosition
 	isEditing          bool
 	editBuffer         string
 	logMessages        []string // Changed from single string to slice
 	projectId          string
-	isAddingTask       bool   // Track if we're in "add new task" mode
-	newTaskBuffer      string // Buffer for new task name input
-	shouldAutoScroll   bool   // Flag to control when to auto-scroll log
+	config             ReportConfig
+	isAddingTask       bool // Track if we're in "add new task" mode
+	isShowingEntries   bool // The entries of the selected cell are shown in a popup
+	selectedEntry      int  // Index of the selected entry in the popup
+	isEditingEntry     bool
+	entryEditBuffer    string
+	isConfirming       bool // A question is shown in the confirmation popup
+	confirmQuestion    string
+	confirmAction      func(*gocui.Gui) error // Runs when the question is confirmed
+	confirmReturnView  string                 // Focused again when the popup closes
+	newTaskBuffer      string                 // Buffer for new task name input
+	newTaskProject     int                    // Index of the project selected for the new task in projectChoices
+	shouldAutoScroll   bool                   // Flag to control when to auto-scroll log
 }
 
 func RenderReport(
-	c *clockify.Clockify,
+	ctx context.Context,
+	t tracker.TimeTracker,
+	l *linear.Linear,
+	gl *gitlab.Gitlab,
 	projectId string,
-	month time.Month,
-	data []clockify.ReportTimeEntry,
+	config ReportConfig,
+	projects []tracker.Project,
+	projectsErr error,
+	from time.Time,
+	data []tracker.ReportTimeEntry,
 	linearLastActivity []linear.LastActivityItem,
+	linearErr error,
 	gitlabLastActivity []gitlab.LastActivityItem,
+	gitlabErr error,
 ) {
-	reportMonth := time.Date(time.Now().Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	reportPeriod := monthPeriod(from)
+	if config.Week {
+		reportPeriod = weekPeriod(from)
+	}
 	g, err := gocui.NewGui(gocui.OutputNormal)
 	g.InputEsc = true
 	g.Highlight = true
@@ -68,25 +139,45 @@ func RenderReport(
 	}
 	defer g.Close()
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	ui := &ReportUI{
-		clockifyClient:     c,
+		cancel:             cancel,
+		timeTracker:        t,
+		linearClient:       l,
+		gitlabClient:       gl,
 		linearLastActivity: linearLastActivity,
+		linearErr:          linearErr,
 		gitlabLastActivity: gitlabLastActivity,
+		gitlabErr:          gitlabErr,
 		data:               data,
-		reportMonth:        reportMonth,
-		days:               datetimeutils.DaysInMonth(reportMonth),
+		period:             reportPeriod,
+		days:               reportPeriod.days(),
 		projectId:          projectId,
-	}
-
-	taskDayMap, taskNamesMap, taskDayIDMap := groupDataByTaskAndDay(data)
-	ui.taskDayMap = taskDayMap
-	ui.taskDayIDMap = taskDayIDMap
+		config:             config,
+		projects:           projects,
+		projectNames:       make(map[string]string, len(projects)),
+		taskNames:          map[string]string{},
+		tagNames:           map[string]string{},
+		taskProjects:       map[string]bool{},
+	}
+	for _, project := range projects {
+		ui.projectNames[project.ID] = project.Name
+	}
+
+	// Report retried requests in the log panel, g.Update makes it safe to call from the background refresh
+	ui.ctx = httpretry.WithObserver(ctx, func(e httpretry.RetryEvent) {
+		g.Update(func(g *gocui.Gui) error {
+			ui.logInfo(e.String())
+			return nil
+		})
+	})
 
-	ui.taskNames = make([]string, 0, len(taskNamesMap))
-	for task := range taskNamesMap {
-		ui.taskNames = append(ui.taskNames, task)
+	ui.setData(data)
+	if projectsErr != nil {
+		ui.logError(fmt.Sprintf("Failed to load projects, showing their IDs instead: %s", describeError(projectsErr)))
 	}
-	sort.Strings(ui.taskNames)
 
 	g.SetManagerFunc(func(g *gocui.Gui) error {
 		return ui.layout(g)
@@ -97,11 +188,67 @@ func RenderReport(
 		return
 	}
 
+	go redrawEveryMinute(ctx, g)
+
 	if err := g.MainLoop(); err != nil && err != gocui.ErrQuit {
 		slog.Error("GUI main loop failed", "error", err)
 	}
 }
 
+// redrawEveryMinute lays the views out again each minute until ctx is cancelled, the table keys its render cache by
+// the minute so that running timers and the balance stay current while no key is pressed.
+func redrawEveryMinute(ctx context.Context, g *gocui.Gui) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			g.Update(func(g *gocui.Gui) error { return nil })
+		}
+	}
+}
+
+func (ui *ReportUI) setData(data []tracker.ReportTimeEntry) {
+	ui.tableVersion++
+	ui.data = data
+	ui.cells = groupDataByRowAndDay(data)
+
+	ui.rows = make([]rowKey, 0, len(ui.cells))
+	for row := range ui.cells {
+		ui.rows = append(ui.rows, row)
+	}
+	ui.sortRows()
+}
+
+// sortRows orders the rows by project name and description, keeping the rows of each project together.
+func (ui *ReportUI) sortRows() {
+	ui.tableVersion++
+	sort.Slice(ui.rows, func(i, j int) bool {
+		a, b := ui.rows[i], ui.rows[j]
+		if a.ProjectID != b.ProjectID {
+			if nameA, nameB := ui.projectName(a.ProjectID), ui.projectName(b.ProjectID); nameA != nameB {
+				return nameA < nameB
+			}
+			return a.ProjectID < b.ProjectID
+		}
+		return a.Description < b.Description
+	})
+}
+
+// projectName returns the name of the project, or its ID when the projects could not be loaded.
+func (ui *ReportUI) projectName(ID string) string {
+	if ID == "" {
+		return "No project"
+	}
+	if name, ok := ui.projectNames[ID]; ok {
+		return name
+	}
+	return ID
+}
+
 func (ui *ReportUI) layout(g *gocui.Gui) error {
 	maxX, maxY := g.Size()
 
@@ -157,9 +304,9 @@ func (ui *ReportUI) layout(g *gocui.Gui) error {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
-		v.Title = fmt.Sprintf(" Time Report - %s ", ui.reportMonth.Format("January 2006"))
 		v.Wrap = false
 		v.Autoscroll = true
+		ui.renderedTable = tableState{}
 
 		if _, err := g.SetCurrentView("table"); err != nil {
 			return err
@@ -187,49 +334,26 @@ func (ui *ReportUI) layout(g *gocui.Gui) error {
 		v.Frame = false // No border for help text
 	}
 
-	if ui.isAddingTask {
-		popupWidth := 50
-		popupHeight := 5
-		x0 := (maxX - popupWidth) / 2
-		y0 := (maxY - popupHeight) / 2
-		x1 := x0 + popupWidth
-		y1 := y0 + popupHeight
-
-		if v, err := g.SetView("taskPopup", x0, y0, x1, y1); err != nil {
-			if err != gocui.ErrUnknownView {
-				return err
-			}
-			v.Title = " Add New Task "
-			v.Wrap = true
-			v.Editable = true
-			v.Editor = &taskEditor{ui: ui}
-
-			if _, err := g.SetCurrentView("taskPopup"); err != nil {
-				return err
-			}
-		}
-
-		if v, err := g.View("taskPopup"); err == nil {
-			v.Clear()
-			fmt.Fprintf(v, "Task name: %s\n\nPress Enter to confirm, Esc to cancel", ui.newTaskBuffer)
-			v.SetCursor(len(ui.newTaskBuffer), 0)
-		}
-	} else {
-		if err := g.DeleteView("taskPopup"); err != nil && err != gocui.ErrUnknownView {
-			return err
-		}
+	if err := ui.layoutTaskPopup(g, maxX, maxY); err != nil {
+		return err
+	}
+	if err := ui.layoutEntryPopup(g, maxX, maxY); err != nil {
+		return err
+	}
+	if err := ui.layoutConfirmPopup(g, maxX, maxY); err != nil {
+		return err
 	}
 
 	if v, err := g.View("table"); err == nil {
-		v.Clear()
-		tableContent := ui.buildTable()
-		fmt.Fprint(v, tableContent)
+		ui.renderTable(v)
 	}
 
 	// Update content for activity views (only in 4-panel layout)
 	if v, err := g.View("linearActivity"); err == nil {
 		v.Clear()
-		if len(ui.linearLastActivity) > 0 {
+		if ui.linearErr != nil {
+			fmt.Fprintf(v, "Failed to load Linear activity: %v\n", ui.linearErr)
+		} else if len(ui.linearLastActivity) > 0 {
 			for _, item := range ui.linearLastActivity {
 				// Parse the updated_at time and format it
 				updatedTime, err := time.Parse(time.RFC3339, item.UpdatedAt)
@@ -249,7 +373,9 @@ func (ui *ReportUI) layout(g *gocui.Gui) error {
 
 	if v, err := g.View("gitActivity"); err == nil {
 		v.Clear()
-		if len(ui.gitlabLastActivity) > 0 {
+		if ui.gitlabErr != nil {
+			fmt.Fprintf(v, "Failed to load Git activity: %v\n", ui.gitlabErr)
+		} else if len(ui.gitlabLastActivity) > 0 {
 			for _, item := range ui.gitlabLastActivity {
 				name := "N/A"
 				if item.Title != nil {
@@ -286,11 +412,11 @@ func (ui *ReportUI) layout(g *gocui.Gui) error {
 		v.Clear()
 		helpText := "\033[1mArrow keys\033[0m: Navigate | \033[1mEnter\033[0m: Edit/Save | " +
 			"\033[1mQ/Esc\033[0m: Cancel | \033[1mCtrl+N\033[0m: Add new task | " +
-			"\033[1mCtrl+D\033[0m: Delete entry | \033[1mCtrl+R\033[0m: Refresh | " +
+			"\033[1mCtrl+E\033[0m: Entries of cell | \033[1mCtrl+D\033[0m: Delete entries | \033[1mCtrl+R\033[0m: Refresh | " +
 			"\033[1mCtrl+T\033[0m: Focus table | \033[1mCtrl+L\033[0m: Focus Linear | " +
 			"\033[1mCtrl+G\033[0m: Focus Git | \033[1mCtrl+C\033[0m: Exit"
 		fmt.Fprint(v, helpText)
-		fmt.Fprint(v, "\nDuration format: 1h30m, 2h, 45m, etc.")
+		fmt.Fprint(v, "\n\033[1m[ / ]\033[0m: Previous/next month or week | \033[1mW\033[0m: Week/month view | Duration format: 1h30m, 2h, 45m, etc.")
 	}
 
 	return nil
@@ -307,8 +433,8 @@ func (ui *ReportUI) simpleLayout(g *gocui.Gui) error {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
-		v.Title = fmt.Sprintf(" Time Report - %s ", ui.reportMonth.Format("January 2006"))
 		v.Wrap = false
+		ui.renderedTable = tableState{}
 
 		if _, err := g.SetCurrentView("table"); err != nil {
 			return err
@@ -325,44 +451,19 @@ func (ui *ReportUI) simpleLayout(g *gocui.Gui) error {
 	}
 
 	// Handle popup for adding tasks (same as main layout)
-	if ui.isAddingTask {
-		popupWidth := 50
-		popupHeight := 5
-		x0 := (maxX - popupWidth) / 2
-		y0 := (maxY - popupHeight) / 2
-		x1 := x0 + popupWidth
-		y1 := y0 + popupHeight
-
-		if v, err := g.SetView("taskPopup", x0, y0, x1, y1); err != nil {
-			if err != gocui.ErrUnknownView {
-				return err
-			}
-			v.Title = " Add New Task "
-			v.Wrap = true
-			v.Editable = true
-			v.Editor = &taskEditor{ui: ui}
-
-			if _, err := g.SetCurrentView("taskPopup"); err != nil {
-				return err
-			}
-		}
-
-		if v, err := g.View("taskPopup"); err == nil {
-			v.Clear()
-			fmt.Fprintf(v, "Task name: %s\n\nPress Enter to confirm, Esc to cancel", ui.newTaskBuffer)
-			v.SetCursor(len(ui.newTaskBuffer), 0)
-		}
-	} else {
-		if err := g.DeleteView("taskPopup"); err != nil && err != gocui.ErrUnknownView {
-			return err
-		}
+	if err := ui.layoutTaskPopup(g, maxX, maxY); err != nil {
+		return err
+	}
+	if err := ui.layoutEntryPopup(g, maxX, maxY); err != nil {
+		return err
+	}
+	if err := ui.layoutConfirmPopup(g, maxX, maxY); err != nil {
+		return err
 	}
 
 	// Update table content
 	if v, err := g.View("table"); err == nil {
-		v.Clear()
-		tableContent := ui.buildTable()
-		fmt.Fprint(v, tableContent)
+		ui.renderTable(v)
 	}
 
 	// Update log content
@@ -383,72 +484,163 @@ func (ui *ReportUI) simpleLayout(g *gocui.Gui) error {
 	return nil
 }
 
-func (ui *ReportUI) buildTable() string {
+// renderTable draws the table when it changed since the last layout. The task and total columns stay in place while
+// the days scroll to keep the selected cell visible.
+func (ui *ReportUI) renderTable(v *gocui.View) {
+	width, _ := v.Size()
+	state := tableState{
+		width:      width,
+		selected:   ui.selectedCell,
+		isEditing:  ui.isEditing,
+		editBuffer: ui.editBuffer,
+		version:    ui.tableVersion,
+		minute:     time.Now().Unix() / 60,
+	}
+	if state == ui.renderedTable {
+		return
+	}
+	ui.renderedTable = state
+
+	first, last := ui.visibleDays(width)
+	v.Clear()
+	v.Title = ui.tableTitle(first, last)
+	fmt.Fprint(v, ui.buildTable(first, last))
+}
+
+// visibleDays returns the indexes of the first and after the last day fitting the width, scrolled so that the
+// selected day is visible.
+func (ui *ReportUI) visibleDays(width int) (int, int) {
+	frozenWidth := taskColumnWidth + dayColumnWidth + len(" | ")
+	count := min(max((width-frozenWidth)/ui.cellWidth(), 1), len(ui.days))
+
+	if ui.selectedCell.DayIndex < ui.firstDay {
+		ui.firstDay = ui.selectedCell.DayIndex
+	}
+	if ui.selectedCell.DayIndex >= ui.firstDay+count {
+		ui.firstDay = ui.selectedCell.DayIndex - count + 1
+	}
+	// Show as many days as fit when the view gets wider
+	ui.firstDay = max(min(ui.firstDay, len(ui.days)-count), 0)
+
+	return ui.firstDay, ui.firstDay + count
+}
+
+func (ui *ReportUI) tableTitle(first int, last int) string {
+	title := fmt.Sprintf(" Time Report - %s (%d entries) ", ui.period, len(ui.data))
+	if first > 0 || last < len(ui.days) {
+		title += fmt.Sprintf("- showing %s to %s ",
+			ui.period.date(ui.days[first]).Format("Jan 2"), ui.period.date(ui.days[last-1]).Format("Jan 2"))
+	}
+	return title
+}
+
+// buildTable renders the task and total columns with the days from first to before last.
+func (ui *ReportUI) buildTable(first int, last int) string {
 	var sb strings.Builder
+	days := ui.days[first:last]
 
-	ui.buildHeaders(&sb)
-	ui.addSeparatorLine(&sb)
-	ui.buildTaskRows(&sb)
-	ui.addTotalsRow(&sb)
+	ui.buildHeaders(&sb, days)
+	ui.addSeparatorLine(&sb, days)
+	ui.buildTaskRows(&sb, first, days)
+	ui.addTotalsRow(&sb, days)
 
 	return sb.String()
 }
 
-func (ui *ReportUI) buildHeaders(sb *strings.Builder) {
+func (ui *ReportUI) buildHeaders(sb *strings.Builder, days []int) {
 	sb.WriteString(fmt.Sprintf("%-*s", taskColumnWidth, ""))
 	sb.WriteString(fmt.Sprintf("%*s", dayColumnWidth, ""))
 	sb.WriteString(" | ")
-	for _, day := range ui.days {
-		sb.WriteString(fmt.Sprintf("%*d", dayColumnWidth, day))
+	for _, day := range days {
+		if ui.period.week {
+			header := ui.period.date(day).Format("Jan 2")
+			if dayOff, ok := ui.config.Calendar.DayOff(ui.period.date(day)); ok {
+				// Name the holiday in front of the date
+				header = truncateString(dayOff.Name, weekColumnWidth-len(header)-2) + " " + header
+			}
+			sb.WriteString(fmt.Sprintf("%*s", weekColumnWidth, header))
+		} else {
+			sb.WriteString(fmt.Sprintf("%*d", dayColumnWidth, day))
+		}
 	}
 	sb.WriteString("\n")
 
 	sb.WriteString(fmt.Sprintf("%-*s", taskColumnWidth, "Task"))
 	sb.WriteString(fmt.Sprintf("%*s", dayColumnWidth, "Total"))
 	sb.WriteString(" | ")
-	for _, day := range ui.days {
-		date := time.Date(ui.reportMonth.Year(), ui.reportMonth.Month(), day, 0, 0, 0, 0, time.UTC)
-		dayName := date.Format("Mon")
-		sb.WriteString(fmt.Sprintf("%*s", dayColumnWidth, dayName[:ellipsisLength]))
+	for _, day := range days {
+		dayName := ui.period.date(day).Format("Mon")
+		sb.WriteString(fmt.Sprintf("%*s", ui.cellWidth(), dayName[:ellipsisLength]))
 	}
 	sb.WriteString("\n")
 }
 
-func (ui *ReportUI) addSeparatorLine(sb *strings.Builder) {
+func (ui *ReportUI) addSeparatorLine(sb *strings.Builder, days []int) {
 	// Use box-drawing characters for a fully connected line
 	sb.WriteString(strings.Repeat("─", taskColumnWidth))
 	sb.WriteString(strings.Repeat("─", dayColumnWidth))
 	sb.WriteString("─┼─") // horizontal with cross junction
-	for range ui.days {
-		sb.WriteString(strings.Repeat("─", dayColumnWidth))
+	for range days {
+		sb.WriteString(strings.Repeat("─", ui.cellWidth()))
 	}
 	sb.WriteString("\n")
 }
 
-func (ui *ReportUI) buildTaskRows(sb *strings.Builder) {
-	for taskIndex, task := range ui.taskNames {
-		sb.WriteString(fmt.Sprintf("%-*s", taskColumnWidth, truncateString(task, taskColumnWidth)))
+// buildTaskRows renders the rows with the given days, first is the index of the first of them in ui.days.
+func (ui *ReportUI) buildTaskRows(sb *strings.Builder, first int, days []int) {
+	for rowIndex, row := range ui.rows {
+		if rowIndex == 0 || ui.rows[rowIndex-1].ProjectID != row.ProjectID {
+			ui.appendProjectHeader(sb, row.ProjectID, days)
+		}
+
+		sb.WriteString(fmt.Sprintf("%-*s", taskColumnWidth, truncateString("  "+row.Description, taskColumnWidth)))
 
 		// Add task total column
-		taskTotal := ui.calculateTaskTotal(task)
-		ui.appendDurationCell(sb, taskTotal)
+		rowTotal := ui.calculateRowTotal(row)
+		ui.appendDurationCell(sb, rowTotal)
 		sb.WriteString(" | ")
 
 		// Add daily columns
-		for dayIndex, day := range ui.days {
-			duration := ui.taskDayMap[task][day]
-			isSelected := ui.selectedCell.TaskIndex == taskIndex && ui.selectedCell.DayIndex == dayIndex
-			ui.appendEditableCell(sb, duration, isSelected, ui.isWeekend(day))
+		for i, day := range days {
+			isSelected := ui.selectedCell.TaskIndex == rowIndex && ui.selectedCell.DayIndex == first+i
+			ui.appendEditableCell(sb, ui.cells[row][day], isSelected, ui.emptyPlaceholder(day))
 		}
 		sb.WriteString("\n")
 	}
 }
 
-func (ui *ReportUI) calculateTaskTotal(task string) time.Duration {
+// appendProjectHeader adds the name of the project in bold with its subtotals, the rows of the project follow it.
+func (ui *ReportUI) appendProjectHeader(sb *strings.Builder, projectID string, days []int) {
+	sb.WriteString("\033[1m")
+	sb.WriteString(fmt.Sprintf("%-*s", taskColumnWidth, truncateString(ui.projectName(projectID), taskColumnWidth)))
+	ui.appendDurationCell(sb, ui.calculateProjectTotal(projectID, ui.days...))
+	sb.WriteString(" | ")
+	for _, day := range days {
+		if subtotal := ui.calculateProjectTotal(projectID, day); subtotal > 0 {
+			sb.WriteString(fmt.Sprintf("%*s", ui.cellWidth(), datetimeutils.ShortDur(subtotal)))
+		} else {
+			sb.WriteString(strings.Repeat(" ", ui.cellWidth()))
+		}
+	}
+	sb.WriteString("\033[0m\n")
+}
+
+func (ui *ReportUI) calculateRowTotal(row rowKey) time.Duration {
 	total := time.Duration(0)
 	for _, day := range ui.days {
-		if duration, exists := ui.taskDayMap[task][day]; exists {
-			total += duration
+		total += cellDuration(ui.cells[row][day])
+	}
+	return total
+}
+
+func (ui *ReportUI) calculateProjectTotal(projectID string, days ...int) time.Duration {
+	total := time.Duration(0)
+	for _, row := range ui.rows {
+		if row.ProjectID != projectID {
+			continue
+		}
+		for _, day := range days {
+			total += cellDuration(ui.cells[row][day])
 		}
 	}
 	return total
@@ -462,104 +654,235 @@ func (ui *ReportUI) appendDurationCell(sb *strings.Builder, duration time.Durati
 	}
 }
 
-func (ui *ReportUI) appendEditableCell(sb *strings.Builder, duration time.Duration, isSelected bool, isWeekend bool) {
+func (ui *ReportUI) appendEditableCell(sb *strings.Builder, entries []tracker.ReportTimeEntry, isSelected bool, placeholder string) {
 	var cellContent string
 	if ui.isEditing && isSelected {
 		cellContent = "[" + ui.editBuffer + "]"
-	} else if duration > 0 {
+	} else if duration := cellDuration(entries); duration > 0 {
 		cellContent = datetimeutils.ShortDur(duration)
-	} else {
-		if isWeekend {
-			cellContent = weekendPlaceholder
-		} else {
-			cellContent = "-"
+		if ui.period.week {
+			cellContent += " " + cellSpan(entries)
 		}
+	} else {
+		cellContent = placeholder
 	}
 
 	if isSelected && !ui.isEditing {
 		cellContent = ">" + cellContent + "<"
 	}
 
-	sb.WriteString(fmt.Sprintf("%*s", dayColumnWidth, cellContent))
+	sb.WriteString(fmt.Sprintf("%*s", ui.cellWidth(), cellContent))
 }
 
-func (ui *ReportUI) addTotalsRow(sb *strings.Builder) {
-	ui.addSeparatorLine(sb)
+// cellWidth returns the width of the day columns, the week view has room for the start and end of the cells.
+func (ui *ReportUI) cellWidth() int {
+	if ui.period.week {
+		return weekColumnWidth
+	}
+	return dayColumnWidth
+}
+
+// cellSpan returns the start of the first entry and the end of the last one, entries are sorted by their start.
+func cellSpan(entries []tracker.ReportTimeEntry) string {
+	end := entries[0].TimeInterval.End
+	for _, entry := range entries {
+		if entry.TimeInterval.End.IsZero() {
+			return entries[0].TimeInterval.Start.Local().Format("15:04") + "-now"
+		}
+		if entry.TimeInterval.End.After(end) {
+			end = entry.TimeInterval.End
+		}
+	}
+	return entries[0].TimeInterval.Start.Local().Format("15:04") + "-" + end.Local().Format("15:04")
+}
+
+// addTotalsRow adds the totals of the days, with a schedule they are green when they reach the target of the day and
+// red otherwise, and a row with the running balance follows.
+func (ui *ReportUI) addTotalsRow(sb *strings.Builder, days []int) {
+	ui.addSeparatorLine(sb, days)
 	sb.WriteString(fmt.Sprintf("%-*s", taskColumnWidth, "TOTAL"))
 
 	grandTotal := time.Duration(0)
-	for _, task := range ui.taskNames {
-		for _, day := range ui.days {
-			if duration, exists := ui.taskDayMap[task][day]; exists {
-				grandTotal += duration
-			}
-		}
+	for _, row := range ui.rows {
+		grandTotal += ui.calculateRowTotal(row)
 	}
 
+	balance := ui.runningBalance()
+	color := ""
+	if ui.config.Schedule != nil {
+		color = balanceColor(balance[ui.lastDueDay()])
+	}
 	if grandTotal > 0 {
-		sb.WriteString(fmt.Sprintf("%*s", dayColumnWidth, datetimeutils.ShortDur(grandTotal)))
+		sb.WriteString(colored(fmt.Sprintf("%*s", dayColumnWidth, datetimeutils.ShortDur(grandTotal)), color))
 	} else {
 		sb.WriteString(fmt.Sprintf("%*s", dayColumnWidth, "-"))
 	}
 	sb.WriteString(" | ")
 
-	for _, day := range ui.days {
-		totalDuration := time.Duration(0)
-		for _, task := range ui.taskNames {
-			if duration, exists := ui.taskDayMap[task][day]; exists {
-				totalDuration += duration
-			}
-		}
+	for _, day := range days {
+		totalDuration := ui.dayTotal(day)
 
 		if totalDuration > 0 {
-			sb.WriteString(fmt.Sprintf("%*s", dayColumnWidth, datetimeutils.ShortDur(totalDuration)))
+			sb.WriteString(colored(fmt.Sprintf("%*s", ui.cellWidth(), datetimeutils.ShortDur(totalDuration)), ui.dayColor(day)))
 		} else {
-			if ui.isWeekend(day) {
-				sb.WriteString(fmt.Sprintf("%*s", dayColumnWidth, weekendPlaceholder))
-			} else {
-				sb.WriteString(fmt.Sprintf("%*s", dayColumnWidth, "-"))
-			}
+			sb.WriteString(colored(fmt.Sprintf("%*s", ui.cellWidth(), ui.emptyPlaceholder(day)), ui.dayColor(day)))
+		}
+	}
+	sb.WriteString("\n")
+
+	if ui.config.Schedule == nil {
+		return
+	}
+
+	// The balance of the period to date, then the running balance at the end of every day up to today
+	sb.WriteString(fmt.Sprintf("%-*s", taskColumnWidth, "BALANCE"))
+	total := balance[ui.lastDueDay()]
+	sb.WriteString(colored(fmt.Sprintf("%*s", dayColumnWidth, datetimeutils.SignedDur(total)), balanceColor(total)))
+	sb.WriteString(" | ")
+	for _, day := range days {
+		if running, ok := balance[day]; ok {
+			sb.WriteString(colored(fmt.Sprintf("%*s", ui.cellWidth(), datetimeutils.SignedDur(running)), balanceColor(running)))
+		} else {
+			sb.WriteString(strings.Repeat(" ", ui.cellWidth()))
 		}
 	}
 	sb.WriteString("\n")
 }
 
-// isWeekend reports whether the given day of the currently selected month is a weekend (Saturday or Sunday)
+func (ui *ReportUI) dayTotal(day int) time.Duration {
+	total := time.Duration(0)
+	for _, row := range ui.rows {
+		total += cellDuration(ui.cells[row][day])
+	}
+	return total
+}
+
+// isDue reports whether the day is today or in the past, only those count towards the targets.
+func (ui *ReportUI) isDue(day int) bool {
+	now := time.Now()
+	return !ui.period.date(day).After(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+// lastDueDay returns the last day of the period that is due, or 0 when the whole period is in the future.
+func (ui *ReportUI) lastDueDay() int {
+	last := 0
+	for _, day := range ui.days {
+		if ui.isDue(day) {
+			last = day
+		}
+	}
+	return last
+}
+
+// runningBalance returns the difference between the logged and the expected time from the start of the period to
+// the end of every due day. The balance of a period in the future is stored under day 0.
+func (ui *ReportUI) runningBalance() map[int]time.Duration {
+	balance := map[int]time.Duration{0: 0}
+	running := time.Duration(0)
+	for _, day := range ui.days {
+		if !ui.isDue(day) {
+			break
+		}
+		running += ui.dayTotal(day) - ui.config.Schedule.For(ui.period.date(day))
+		balance[day] = running
+	}
+	return balance
+}
+
+// dayColor returns the color of the total of a due day with a target, green once the target is reached.
+func (ui *ReportUI) dayColor(day int) string {
+	target := ui.config.Schedule.For(ui.period.date(day))
+	if target == 0 || !ui.isDue(day) {
+		return ""
+	}
+	return balanceColor(ui.dayTotal(day) - target)
+}
+
+func balanceColor(balance time.Duration) string {
+	if balance < 0 {
+		return colorRed
+	}
+	return colorGreen
+}
+
+func colored(text string, color string) string {
+	if color == "" {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// isWeekend reports whether the given day of the shown period is a weekend (Saturday or Sunday)
 func (ui *ReportUI) isWeekend(day int) bool {
-	date := time.Date(ui.reportMonth.Year(), ui.reportMonth.Month(), day, 0, 0, 0, 0, time.UTC)
-	wd := date.Weekday()
+	wd := ui.period.date(day).Weekday()
 	return wd == time.Saturday || wd == time.Sunday
 }
 
-func groupDataByTaskAndDay(data []clockify.ReportTimeEntry) (map[string]map[int]time.Duration, map[string]bool, map[string]map[int]string) {
-	taskDayMap := make(map[string]map[int]time.Duration)
-	taskNames := make(map[string]bool)
-	taskDayIDMap := make(map[string]map[int]string)
+// emptyPlaceholder returns what an empty cell of the day shows, holidays and leave are marked like weekends.
+func (ui *ReportUI) emptyPlaceholder(day int) string {
+	if dayOff, ok := ui.config.Calendar.DayOff(ui.period.date(day)); ok {
+		if dayOff.Kind == holidays.Leave {
+			return leavePlaceholder
+		}
+		return holidayPlaceholder
+	}
+	if ui.isWeekend(day) {
+		return weekendPlaceholder
+	}
+	return "-"
+}
+
+func groupDataByRowAndDay(data []tracker.ReportTimeEntry) map[rowKey]map[int][]tracker.ReportTimeEntry {
+	cells := make(map[rowKey]map[int][]tracker.ReportTimeEntry)
 
 	for _, entry := range data {
 		task := entry.Description
 		if task == "" {
 			task = "Unnamed Task"
 		}
-		taskNames[task] = true
+		row := rowKey{ProjectID: entry.ProjectID, Description: task}
 
-		if taskDayMap[task] == nil {
-			taskDayMap[task] = make(map[int]time.Duration)
-		}
-		if taskDayIDMap[task] == nil {
-			taskDayIDMap[task] = make(map[int]string)
+		if cells[row] == nil {
+			cells[row] = make(map[int][]tracker.ReportTimeEntry)
 		}
+		day := entry.TimeInterval.Start.Local().Day()
+		cells[row][day] = append(cells[row][day], entry)
+	}
 
-		day := entry.TimeInterval.Start.Day()
-		duration := entry.TimeInterval.End.Sub(entry.TimeInterval.Start)
+	for _, days := range cells {
+		for _, entries := range days {
+			sortEntries(entries)
+		}
+	}
+	return cells
+}
 
-		// Since each day should only have one task entry, we sum up durations
-		// and keep the last entry's ID (this represents all entries for this task+day)
-		taskDayMap[task][day] += duration
-		taskDayIDMap[task][day] = entry.ID
+// setCell replaces the entries of the cell, they are sorted by their start.
+func (ui *ReportUI) setCell(row rowKey, day int, entries []tracker.ReportTimeEntry) {
+	ui.tableVersion++
+	if ui.cells[row] == nil {
+		ui.cells[row] = make(map[int][]tracker.ReportTimeEntry)
 	}
+	if len(entries) == 0 {
+		delete(ui.cells[row], day)
+		return
+	}
+	sortEntries(entries)
+	ui.cells[row][day] = entries
+}
 
-	return taskDayMap, taskNames, taskDayIDMap
+func sortEntries(entries []tracker.ReportTimeEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].TimeInterval.Start.Before(entries[j].TimeInterval.Start)
+	})
+}
+
+func cellDuration(entries []tracker.ReportTimeEntry) time.Duration {
+	total := time.Duration(0)
+	for _, entry := range entries {
+		total += entry.Duration()
+	}
+	return total
 }
 
 func truncateString(s string, maxLen int) string {
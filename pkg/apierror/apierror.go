@@ -0,0 +1,147 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// APIError is a failed request to one of the APIs, parsed from the error response of the service.
+type APIError struct {
+	Service    string
+	StatusCode int
+	Code       string // Service specific error code, empty if the service did not send any
+	Message    string
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s API request failed with status %d", e.Service, e.StatusCode)
+	if e.Code != "" {
+		fmt.Fprintf(&sb, " (code %s)", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&sb, ": %s", e.Message)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&sb, " [request ID %s]", e.RequestID)
+	}
+	return sb.String()
+}
+
+// New creates the error from the response of a failed request and its already read body.
+func New(service string, resp *http.Response, body []byte) *APIError {
+	code, message := parseBody(body)
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+
+	return &APIError{
+		Service:    service,
+		StatusCode: resp.StatusCode,
+		Code:       code,
+		Message:    message,
+		RequestID:  requestID(resp.Header),
+	}
+}
+
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func IsValidation(err error) bool {
+	return hasStatus(err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+}
+
+// IsLocked reports whether the entry cannot be changed, e.g. because the timesheet was already approved.
+// The services report it with different status codes, so the message is checked as well.
+func IsLocked(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusLocked || strings.Contains(strings.ToLower(apiErr.Message), "locked")
+}
+
+func hasStatus(err error, statusCodes ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, statusCode := range statusCodes {
+		if apiErr.StatusCode == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBody extracts the error code and message from the error shapes used by the supported services.
+func parseBody(body []byte) (string, string) {
+	var response struct {
+		Code             json.RawMessage `json:"code"`
+		Message          string          `json:"message"`
+		Error            string          `json:"error"`
+		ErrorDescription string          `json:"error_description"`
+		Errors           []struct {
+			Message    string `json:"message"`
+			Extensions struct {
+				Code string `json:"code"`
+			} `json:"extensions"`
+		} `json:"errors"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		// Some services respond with a JSON string or plain text
+		var message string
+		if err := json.Unmarshal(body, &message); err == nil {
+			return "", message
+		}
+		return "", strings.TrimSpace(string(body))
+	}
+
+	switch {
+	case len(response.Errors) > 0:
+		return response.Errors[0].Extensions.Code, response.Errors[0].Message
+	case response.Message != "":
+		return rawCode(response.Code), response.Message
+	case response.ErrorDescription != "":
+		return response.Error, response.ErrorDescription
+	default:
+		return rawCode(response.Code), response.Error
+	}
+}
+
+// rawCode formats the error code, which is a number in some APIs and a string in others.
+func rawCode(raw json.RawMessage) string {
+	var code string
+	if err := json.Unmarshal(raw, &code); err == nil {
+		return code
+	}
+	var numericCode int
+	if err := json.Unmarshal(raw, &numericCode); err == nil {
+		return strconv.Itoa(numericCode)
+	}
+	return ""
+}
+
+func requestID(header http.Header) string {
+	for _, name := range []string{"X-Request-Id", "X-Correlation-Id", "Request-Id"} {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
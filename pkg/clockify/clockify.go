@@ -2,56 +2,96 @@ package clockify
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/apierror"
+	"github.com/andrejsoucek/chronos/pkg/httpretry"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
 )
 
+const reportPageSize = 1000
+
+var ErrNoRunningTimer = tracker.ErrNoRunningTimer
+
 type ClockifyConfig struct {
 	APIKey      string
 	BaseURL     string
 	UserURL     string
 	WorkspaceID string
 	UserID      string
+	HTTPClient  *http.Client // Optional, httpretry.NewClient is used when nil
+}
+
+type TimeEntry = tracker.TimeEntry
+
+type ReportTimeEntry = tracker.ReportTimeEntry
+
+type ReportPage struct {
+	Entries []ReportTimeEntry
+	Page    int
+	IsLast  bool
+}
+
+type User struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	ActiveWorkspace  string `json:"activeWorkspace"`
+	DefaultWorkspace string `json:"defaultWorkspace"`
+}
+
+type Workspace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Project struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	ClientName string `json:"clientName"`
+	Billable   bool   `json:"billable"`
+	Archived   bool   `json:"archived"`
 }
 
-type TimeEntry struct {
-	Time        time.Time
-	Duration    time.Duration
-	Description string
-	ProjectID   string
+type Task struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ProjectID string `json:"projectId"`
+	Status    string `json:"status"`
 }
 
-type ReportTimeEntry struct {
-	ID           string `json:"id"`
-	Description  string `json:"description"`
-	TimeInterval struct {
-		Start time.Time `json:"start"`
-		End   time.Time `json:"end"`
-	} `json:"timeInterval"`
-	IsLocked bool `json:"isLocked"`
+type Tag struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
 }
 
 type Clockify struct {
 	Config *ClockifyConfig
+	client *http.Client
 }
 
 func NewClockify(config *ClockifyConfig) *Clockify {
 	return &Clockify{
 		Config: config,
+		client: httpretry.ClientOrDefault(config.HTTPClient),
 	}
 }
 
-func (c *Clockify) GetWorkspaceID() (string, error) {
-	req, err := c.prepareReq(http.MethodGet, c.Config.UserURL)
+func (c *Clockify) GetWorkspaceID(ctx context.Context) (string, error) {
+	req, err := c.prepareReq(ctx, http.MethodGet, c.Config.UserURL)
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -76,36 +116,115 @@ func (c *Clockify) GetWorkspaceID() (string, error) {
 		return string(formattedBytes), nil
 	}
 
-	return "", errors.New("failed to get workspace ID")
+	return "", responseError(resp)
 }
 
-func (c *Clockify) LogTime(te *TimeEntry) (string, error) {
-	req, err := c.prepareReq(http.MethodPost, c.Config.BaseURL+"/time-entries")
+func (c *Clockify) GetUser(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.getJSON(ctx, c.Config.UserURL, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetWorkspaces returns the workspaces of the user, it does not need the workspace to be configured.
+func (c *Clockify) GetWorkspaces(ctx context.Context) ([]Workspace, error) {
+	// The workspaces endpoint is a sibling of the user endpoint
+	workspacesURL := strings.TrimSuffix(c.Config.UserURL, "user") + "workspaces"
+
+	var workspaces []Workspace
+	if err := c.getJSON(ctx, workspacesURL, &workspaces); err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+// GetProjects returns the active projects of the workspace.
+func (c *Clockify) GetProjects(ctx context.Context) ([]Project, error) {
+	var projects []Project
+	if err := c.getJSON(ctx, c.Config.BaseURL+"projects?archived=false&page-size=5000", &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// ListProjects returns the active projects of the workspace.
+func (c *Clockify) ListProjects(ctx context.Context) ([]tracker.Project, error) {
+	projects, err := c.GetProjects(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	now := te.Time.Truncate(time.Minute * 30)
-	endTime := now.Format(time.RFC3339)
-	startTime := now.Add(-te.Duration).Format(time.RFC3339)
+	list := make([]tracker.Project, 0, len(projects))
+	for _, project := range projects {
+		list = append(list, tracker.Project{ID: project.ID, Name: project.Name})
+	}
+	return list, nil
+}
 
-	body := map[string]interface{}{
-		"billable":    true,
-		"end":         endTime,
-		"start":       startTime,
-		"projectId":   te.ProjectID,
-		"description": te.Description,
+// GetTasks returns the active tasks of the project.
+func (c *Clockify) GetTasks(ctx context.Context, projectID string) ([]Task, error) {
+	var tasks []Task
+	if err := c.getJSON(ctx, c.Config.BaseURL+"projects/"+projectID+"/tasks?is-active=true&page-size=5000", &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ListTasks returns the active tasks of the project.
+func (c *Clockify) ListTasks(ctx context.Context, projectID string) ([]tracker.Task, error) {
+	tasks, err := c.GetTasks(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]tracker.Task, 0, len(tasks))
+	for _, task := range tasks {
+		list = append(list, tracker.Task{ID: task.ID, Name: task.Name})
+	}
+	return list, nil
+}
+
+// GetTags returns the tags of the workspace that are not archived.
+func (c *Clockify) GetTags(ctx context.Context) ([]Tag, error) {
+	var tags []Tag
+	if err := c.getJSON(ctx, c.Config.BaseURL+"tags?archived=false&page-size=5000", &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ListTags returns the tags of the workspace that are not archived.
+func (c *Clockify) ListTags(ctx context.Context) ([]tracker.Tag, error) {
+	tags, err := c.GetTags(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	list := make([]tracker.Tag, 0, len(tags))
+	for _, tag := range tags {
+		list = append(list, tracker.Tag{ID: tag.ID, Name: tag.Name})
+	}
+	return list, nil
+}
+
+func (c *Clockify) LogTime(ctx context.Context, te *TimeEntry) (string, error) {
+	req, err := c.prepareReq(ctx, http.MethodPost, c.Config.BaseURL+"time-entries")
+	if err != nil {
+		return "", err
+	}
+
+	start, end := te.Interval()
+	body := entryBody(te, start, &end)
+
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		return "", err
 	}
 
-	req.Body = io.NopCloser(bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
+	setJSONBody(req, jsonBody)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -115,7 +234,7 @@ func (c *Clockify) LogTime(te *TimeEntry) (string, error) {
 	// Check if the request was successful
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("clockify API returned status %d: %s", resp.StatusCode, string(bodyBytes))
+		return "", apierror.New("clockify", resp, bodyBytes)
 	}
 
 	// Parse response to get the created entry ID
@@ -135,33 +254,23 @@ func (c *Clockify) LogTime(te *TimeEntry) (string, error) {
 	return createdEntry.ID, nil
 }
 
-func (c *Clockify) EditLog(ID string, te *TimeEntry) error {
-	req, err := c.prepareReq(http.MethodPut, c.Config.BaseURL+"/time-entries/"+ID)
+func (c *Clockify) EditLog(ctx context.Context, ID string, te *TimeEntry) error {
+	req, err := c.prepareReq(ctx, http.MethodPut, c.Config.BaseURL+"time-entries/"+ID)
 	if err != nil {
 		return err
 	}
 
-	now := te.Time.Truncate(time.Minute * 30)
-	endTime := now.Format(time.RFC3339)
-	startTime := now.Add(-te.Duration).Format(time.RFC3339)
-
-	body := map[string]interface{}{
-		"billable":    true,
-		"end":         endTime,
-		"start":       startTime,
-		"projectId":   te.ProjectID,
-		"description": te.Description,
-	}
+	start, end := te.Interval()
+	body := entryBody(te, start, &end)
 
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		return err
 	}
 
-	req.Body = io.NopCloser(bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
+	setJSONBody(req, jsonBody)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -170,45 +279,202 @@ func (c *Clockify) EditLog(ID string, te *TimeEntry) error {
 
 	// Check if the request was successful
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("clockify API returned status %d: %s", resp.StatusCode, string(bodyBytes))
+		return responseError(resp)
 	}
 
 	return nil
 }
 
-func (c *Clockify) DeleteLog(ID string) error {
-	req, err := c.prepareReq(http.MethodDelete, c.Config.BaseURL+"/time-entries/"+ID)
+func (c *Clockify) DeleteLog(ctx context.Context, ID string) error {
+	req, err := c.prepareReq(ctx, http.MethodDelete, c.Config.BaseURL+"time-entries/"+ID)
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("clockify API returned status %d: %s", resp.StatusCode, string(bodyBytes))
+		return responseError(resp)
 	}
 
 	return nil
 }
 
-func (c *Clockify) GetReport(from time.Time, to time.Time) ([]ReportTimeEntry, error) {
-	url := fmt.Sprintf("%suser/%s/time-entries?start=%s&end=%s&page-size=1000",
+// StartTimer starts an in-progress time entry at te.Time, the duration of the entry is ignored.
+func (c *Clockify) StartTimer(ctx context.Context, te *TimeEntry) (string, error) {
+	req, err := c.prepareReq(ctx, http.MethodPost, c.Config.BaseURL+"time-entries")
+	if err != nil {
+		return "", err
+	}
+
+	body := entryBody(te, te.Time, nil)
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	setJSONBody(req, jsonBody)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", apierror.New("clockify", resp, bodyBytes)
+	}
+
+	var createdEntry struct {
+		ID string `json:"id"`
+	}
+
+	if err := json.Unmarshal(bodyBytes, &createdEntry); err != nil {
+		return "", fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	return createdEntry.ID, nil
+}
+
+// StopTimer stops the in-progress time entry of the user at the given time and returns the stopped entry.
+// ErrNoRunningTimer is returned when there is nothing to stop.
+func (c *Clockify) StopTimer(ctx context.Context, end time.Time) (*ReportTimeEntry, error) {
+	req, err := c.prepareReq(ctx, http.MethodPatch, fmt.Sprintf("%suser/%s/time-entries", c.Config.BaseURL, c.Config.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"end": end.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	setJSONBody(req, jsonBody)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNoRunningTimer
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, apierror.New("clockify", resp, bodyBytes)
+	}
+
+	var stoppedEntry ReportTimeEntry
+	if err := json.Unmarshal(bodyBytes, &stoppedEntry); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	return &stoppedEntry, nil
+}
+
+// GetRunningTimer returns the in-progress time entry of the user or ErrNoRunningTimer.
+func (c *Clockify) GetRunningTimer(ctx context.Context) (*ReportTimeEntry, error) {
+	req, err := c.prepareReq(ctx, http.MethodGet, fmt.Sprintf("%suser/%s/time-entries?in-progress=true", c.Config.BaseURL, c.Config.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var runningEntries []ReportTimeEntry
+	if err := json.Unmarshal(bodyBytes, &runningEntries); err != nil {
+		return nil, err
+	}
+
+	if len(runningEntries) == 0 {
+		return nil, ErrNoRunningTimer
+	}
+
+	return &runningEntries[0], nil
+}
+
+func (c *Clockify) GetReport(ctx context.Context, from time.Time, to time.Time) ([]ReportTimeEntry, error) {
+	var reportEntries []ReportTimeEntry
+	for entry, err := range c.ReportEntries(ctx, from, to) {
+		if err != nil {
+			return nil, err
+		}
+		reportEntries = append(reportEntries, entry)
+	}
+
+	return reportEntries, nil
+}
+
+// ReportEntries iterates over all time entries in the given range, fetching the pages lazily.
+// Iteration stops after the first error.
+func (c *Clockify) ReportEntries(ctx context.Context, from time.Time, to time.Time) iter.Seq2[ReportTimeEntry, error] {
+	return func(yield func(ReportTimeEntry, error) bool) {
+		for page := 1; ; page++ {
+			reportPage, err := c.GetReportPage(ctx, from, to, page)
+			if err != nil {
+				yield(ReportTimeEntry{}, err)
+				return
+			}
+
+			for _, entry := range reportPage.Entries {
+				if !yield(entry, nil) {
+					return
+				}
+			}
+
+			if reportPage.IsLast {
+				return
+			}
+		}
+	}
+}
+
+// GetReportPage fetches a single page (starting at 1) of time entries in the given range.
+func (c *Clockify) GetReportPage(ctx context.Context, from time.Time, to time.Time, page int) (*ReportPage, error) {
+	url := fmt.Sprintf("%suser/%s/time-entries?start=%s&end=%s&page=%d&page-size=%d",
 		c.Config.BaseURL,
 		c.Config.UserID,
 		from.Format("2006-01-02T15:04:05Z"),
-		to.Format("2006-01-02T15:04:05Z"))
+		to.Format("2006-01-02T15:04:05Z"),
+		page,
+		reportPageSize)
 
-	req, err := c.prepareReq(http.MethodGet, url)
+	req, err := c.prepareReq(ctx, http.MethodGet, url)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -225,14 +491,83 @@ func (c *Clockify) GetReport(from time.Time, to time.Time) ([]ReportTimeEntry, e
 			return nil, err
 		}
 
-		return reportEntries, nil
+		// A short page means there is nothing more to fetch, Clockify also marks the last page with a Last-Page header
+		isLast := len(reportEntries) < reportPageSize || resp.Header.Get("Last-Page") == "true"
+
+		return &ReportPage{
+			Entries: reportEntries,
+			Page:    page,
+			IsLast:  isLast,
+		}, nil
+	}
+
+	return nil, responseError(resp)
+}
+
+// entryBody creates the request body of a time entry, the entry is in progress when end is nil.
+func entryBody(te *TimeEntry, start time.Time, end *time.Time) map[string]interface{} {
+	body := map[string]interface{}{
+		"start":       start.Format(time.RFC3339),
+		"description": te.Description,
+	}
+	if te.ProjectID != "" {
+		body["projectId"] = te.ProjectID
+	}
+	if end != nil {
+		body["end"] = end.Format(time.RFC3339)
+	}
+	if te.TaskID != "" {
+		body["taskId"] = te.TaskID
+	}
+	if len(te.TagIDs) > 0 {
+		body["tagIds"] = te.TagIDs
+	}
+	if te.Billable != nil {
+		body["billable"] = *te.Billable
+	}
+	return body
+}
+
+func (c *Clockify) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := c.prepareReq(ctx, http.MethodGet, url)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
 	}
+	return nil
+}
+
+// responseError reads the body of the failed response and creates the error from it.
+func responseError(resp *http.Response) error {
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	return apierror.New("clockify", resp, bodyBytes)
+}
 
-	return nil, errors.New("failed to get report, response code: " + resp.Status)
+// setJSONBody sets the request body in a way that allows sending it again when the request is retried.
+func setJSONBody(req *http.Request, jsonBody []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(jsonBody))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(jsonBody)), nil
+	}
+	req.ContentLength = int64(len(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
 }
 
-func (c *Clockify) prepareReq(method string, url string) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, nil)
+func (c *Clockify) prepareReq(ctx context.Context, method string, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return nil, err
 	}
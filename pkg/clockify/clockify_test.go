@@ -0,0 +1,235 @@
+package clockify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/apierror"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+// newTestClockify creates a client of a stand-in server handling the requests with handler.
+func newTestClockify(t *testing.T, handler http.HandlerFunc) *Clockify {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClockify(&ClockifyConfig{
+		APIKey:      "secret",
+		BaseURL:     server.URL + "/api/v1/workspaces/w1/",
+		UserURL:     server.URL + "/api/v1/user",
+		WorkspaceID: "w1",
+		UserID:      "u1",
+	})
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, body any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		t.Error(err)
+	}
+}
+
+// reportEntries creates count entries of a report page, numbered from first.
+func reportEntries(first int, count int) []map[string]any {
+	entries := make([]map[string]any, 0, count)
+	for i := first; i < first+count; i++ {
+		entries = append(entries, map[string]any{
+			"id":          strconv.Itoa(i),
+			"description": fmt.Sprintf("Entry %d", i),
+			"timeInterval": map[string]any{
+				"start": "2026-03-10T09:00:00Z",
+				"end":   "2026-03-10T09:15:00Z",
+			},
+		})
+	}
+	return entries
+}
+
+func TestGetReport(t *testing.T) {
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.March, 31, 23, 59, 59, 0, time.UTC)
+	var pages []string
+	clockify := newTestClockify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/workspaces/w1/user/u1/time-entries" || r.Header.Get("X-Api-Key") != "secret" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("start") != "2026-03-01T00:00:00Z" || query.Get("end") != "2026-03-31T23:59:59Z" {
+			t.Errorf("unexpected range %s - %s", query.Get("start"), query.Get("end"))
+		}
+		if query.Get("page-size") != strconv.Itoa(reportPageSize) {
+			t.Errorf("unexpected page size %s", query.Get("page-size"))
+		}
+
+		page := query.Get("page")
+		pages = append(pages, page)
+		switch page {
+		case "1":
+			writeJSON(t, w, http.StatusOK, reportEntries(0, reportPageSize))
+		case "2":
+			writeJSON(t, w, http.StatusOK, reportEntries(reportPageSize, reportPageSize))
+		case "3":
+			writeJSON(t, w, http.StatusOK, reportEntries(2*reportPageSize, 3))
+		default:
+			t.Errorf("unexpected page %s", page)
+			writeJSON(t, w, http.StatusOK, []map[string]any{})
+		}
+	})
+
+	entries, err := clockify.GetReport(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("GetReport() failed: %v", err)
+	}
+	if len(pages) != 3 || pages[0] != "1" || pages[1] != "2" || pages[2] != "3" {
+		t.Errorf("fetched pages %v, want 1, 2 and 3", pages)
+	}
+	if len(entries) != 2*reportPageSize+3 {
+		t.Fatalf("GetReport() returned %d entries, want %d", len(entries), 2*reportPageSize+3)
+	}
+	for i, entry := range entries {
+		if entry.ID != strconv.Itoa(i) {
+			t.Fatalf("entry %d has ID %s, the pages must be joined in order", i, entry.ID)
+		}
+	}
+	if entries[0].Duration() != 15*time.Minute {
+		t.Errorf("Duration() = %s, want 15m", entries[0].Duration())
+	}
+}
+
+func TestGetReportLastPageHeader(t *testing.T) {
+	requests := 0
+	clockify := newTestClockify(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Last-Page", "true")
+		writeJSON(t, w, http.StatusOK, reportEntries(0, reportPageSize))
+	})
+
+	entries, err := clockify.GetReport(context.Background(), time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("GetReport() failed: %v", err)
+	}
+	if requests != 1 || len(entries) != reportPageSize {
+		t.Errorf("GetReport() made %d requests for %d entries, want a single full page", requests, len(entries))
+	}
+}
+
+func TestGetReportStopsAtError(t *testing.T) {
+	clockify := newTestClockify(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			writeJSON(t, w, http.StatusOK, reportEntries(0, reportPageSize))
+			return
+		}
+		writeJSON(t, w, http.StatusUnauthorized, map[string]any{"message": "Full authentication is required", "code": 1000})
+	})
+
+	entries, err := clockify.GetReport(context.Background(), time.Now(), time.Now())
+	if !apierror.IsUnauthorized(err) || entries != nil {
+		t.Errorf("GetReport() = %d entries, %v, want an unauthorized error", len(entries), err)
+	}
+}
+
+func TestTimer(t *testing.T) {
+	var started, stopped map[string]any
+	running := false
+	clockify := newTestClockify(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/workspaces/w1/time-entries":
+			if err := json.NewDecoder(r.Body).Decode(&started); err != nil {
+				t.Error(err)
+			}
+			running = true
+			writeJSON(t, w, http.StatusCreated, map[string]any{"id": "e1"})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/workspaces/w1/user/u1/time-entries":
+			if r.URL.Query().Get("in-progress") != "true" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			if !running {
+				writeJSON(t, w, http.StatusOK, []map[string]any{})
+				return
+			}
+			writeJSON(t, w, http.StatusOK, []map[string]any{{
+				"id":           "e1",
+				"description":  "Deploy",
+				"projectId":    "p1",
+				"timeInterval": map[string]any{"start": "2026-03-10T09:00:00Z", "end": nil},
+			}})
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v1/workspaces/w1/user/u1/time-entries":
+			if !running {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if err := json.NewDecoder(r.Body).Decode(&stopped); err != nil {
+				t.Error(err)
+			}
+			running = false
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"id":           "e1",
+				"description":  "Deploy",
+				"timeInterval": map[string]any{"start": "2026-03-10T09:00:00Z", "end": stopped["end"]},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	if _, err := clockify.GetRunningTimer(context.Background()); !errors.Is(err, tracker.ErrNoRunningTimer) {
+		t.Errorf("GetRunningTimer() = %v, want ErrNoRunningTimer", err)
+	}
+
+	start := time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC)
+	id, err := clockify.StartTimer(context.Background(), &TimeEntry{Time: start, Description: "Deploy", ProjectID: "p1", Duration: time.Hour})
+	if err != nil {
+		t.Fatalf("StartTimer() failed: %v", err)
+	}
+	if id != "e1" {
+		t.Errorf("StartTimer() = %s, want e1", id)
+	}
+	if _, ok := started["end"]; ok || started["start"] != "2026-03-10T09:00:00Z" || started["projectId"] != "p1" {
+		t.Errorf("unexpected timer body %v, it must start at 09:00 without an end", started)
+	}
+
+	timer, err := clockify.GetRunningTimer(context.Background())
+	if err != nil {
+		t.Fatalf("GetRunningTimer() failed: %v", err)
+	}
+	if timer.ID != "e1" || timer.Description != "Deploy" || !timer.TimeInterval.End.IsZero() {
+		t.Errorf("unexpected timer %+v", timer)
+	}
+
+	end := time.Date(2026, time.March, 10, 11, 0, 0, 0, time.UTC)
+	entry, err := clockify.StopTimer(context.Background(), end)
+	if err != nil {
+		t.Fatalf("StopTimer() failed: %v", err)
+	}
+	if stopped["end"] != "2026-03-10T11:00:00Z" || !entry.TimeInterval.End.Equal(end) || entry.Duration() != 2*time.Hour {
+		t.Errorf("timer stopped with %v as %+v, want an end at 11:00", stopped, entry)
+	}
+
+	if _, err := clockify.StopTimer(context.Background(), end); !errors.Is(err, ErrNoRunningTimer) {
+		t.Errorf("StopTimer() = %v, want ErrNoRunningTimer", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	clockify := newTestClockify(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, http.StatusBadRequest, map[string]any{"message": "Project not found", "code": 501})
+	})
+
+	_, err := clockify.LogTime(context.Background(), &TimeEntry{Time: time.Now(), Duration: time.Hour, ProjectID: "missing"})
+	var apiErr *apierror.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("LogTime() = %v, want an API error", err)
+	}
+	if !apierror.IsValidation(err) || apiErr.Service != "clockify" || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "Project not found" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
@@ -1,6 +1,7 @@
 package datetimeutils
 
 import (
+	"fmt"
 	"strings"
 	"time"
 )
@@ -16,6 +17,20 @@ func DaysInMonth(t time.Time) []int {
 	return days
 }
 
+// MonthRange returns the first and the last second of the month in the location of t.
+func MonthRange(t time.Time) (time.Time, time.Time) {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return from, from.AddDate(0, 1, 0).Add(-time.Second)
+}
+
+// WeekRange returns the first second of the Monday and the last second of the Sunday of the week of t, in the
+// location of t.
+func WeekRange(t time.Time) (time.Time, time.Time) {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	from := time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+	return from, from.AddDate(0, 0, 7).Add(-time.Second)
+}
+
 func ShortDur(d time.Duration) string {
 	s := d.String()
 	if strings.HasSuffix(s, "m0s") {
@@ -27,3 +42,34 @@ func ShortDur(d time.Duration) string {
 
 	return s
 }
+
+// SignedDur formats the duration like ShortDur with a plus sign when it is positive, e.g. for overtime.
+func SignedDur(d time.Duration) string {
+	if d > 0 {
+		return "+" + ShortDur(d)
+	}
+	return ShortDur(d)
+}
+
+// ParseDate parses a date in the YYYY-MM-DD format or one of the "today" and "yesterday" keywords.
+func ParseDate(s string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(s) {
+	case "", "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	return time.ParseInLocation(time.DateOnly, s, now.Location())
+}
+
+// AtClock returns the given day at the time of day in the HH:MM format.
+func AtClock(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s', expected HH:MM", clock)
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+}
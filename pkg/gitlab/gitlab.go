@@ -1,21 +1,26 @@
 package gitlab
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/apierror"
+	"github.com/andrejsoucek/chronos/pkg/httpretry"
 )
 
 type GitlabConfig struct {
-	APIKey  string
-	BaseURL string
-	UserID  string
+	APIKey     string
+	BaseURL    string
+	UserID     string
+	HTTPClient *http.Client // Optional, httpretry.NewClient is used when nil
 }
 
 type Gitlab struct {
 	Config *GitlabConfig
+	client *http.Client
 }
 
 type LastActivityItem struct {
@@ -27,14 +32,26 @@ type LastActivityItem struct {
 	CreatedAt string `json:"created_at"`
 }
 
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
 func NewGitlab(config *GitlabConfig) *Gitlab {
 	return &Gitlab{
 		Config: config,
+		client: httpretry.ClientOrDefault(config.HTTPClient),
 	}
 }
 
-func (g *Gitlab) GetLastActivity(from time.Time, to time.Time) ([]LastActivityItem, error) {
+func (g *Gitlab) IsConfigured() bool {
+	return g.Config.APIKey != "" && g.Config.BaseURL != "" && g.Config.UserID != ""
+}
+
+func (g *Gitlab) GetLastActivity(ctx context.Context, from time.Time, to time.Time) ([]LastActivityItem, error) {
 	req, err := g.prepareReq(
+		ctx,
 		http.MethodGet,
 		g.Config.BaseURL+"users/"+g.Config.UserID+"/events?before="+to.Format(time.RFC3339)+"&after="+from.Format(time.RFC3339),
 	)
@@ -42,8 +59,7 @@ func (g *Gitlab) GetLastActivity(from time.Time, to time.Time) ([]LastActivityIt
 		return nil, err
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := g.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +71,7 @@ func (g *Gitlab) GetLastActivity(from time.Time, to time.Time) ([]LastActivityIt
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status code %d: %s", resp.StatusCode, string(body))
+		return nil, apierror.New("gitlab", resp, body)
 	}
 
 	var response []LastActivityItem
@@ -66,8 +82,38 @@ func (g *Gitlab) GetLastActivity(from time.Time, to time.Time) ([]LastActivityIt
 	return response, nil
 }
 
-func (g *Gitlab) prepareReq(method string, url string) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, nil)
+// GetCurrentUser returns the user the access token belongs to.
+func (g *Gitlab) GetCurrentUser(ctx context.Context) (*User, error) {
+	req, err := g.prepareReq(ctx, http.MethodGet, g.Config.BaseURL+"user")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apierror.New("gitlab", resp, body)
+	}
+
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (g *Gitlab) prepareReq(ctx context.Context, method string, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,359 @@
+package harvest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/apierror"
+	"github.com/andrejsoucek/chronos/pkg/httpretry"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+const (
+	userAgent = "chronos (https://github.com/andrejsoucek/chronos)"
+
+	timeOfDayLayout = "3:04pm" // Started and ended times of the entries
+)
+
+type HarvestConfig struct {
+	AccessToken string
+	AccountID   string
+	BaseURL     string
+	HTTPClient  *http.Client // Optional, httpretry.NewClient is used when nil
+}
+
+// Harvest is a time tracker backend where every entry belongs to a project and a task. The task name takes
+// the place of the entry description used by the other backends.
+type Harvest struct {
+	Config             *HarvestConfig
+	client             *http.Client
+	userID             int64
+	projectAssignments []ProjectAssignment
+}
+
+type Project struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Code string `json:"code"`
+}
+
+type Task struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type ProjectAssignment struct {
+	Project         Project `json:"project"`
+	TaskAssignments []struct {
+		Task Task `json:"task"`
+	} `json:"task_assignments"`
+}
+
+type timeEntry struct {
+	ID          int64   `json:"id"`
+	SpentDate   string  `json:"spent_date"`
+	Hours       float64 `json:"hours"`
+	Notes       string  `json:"notes"`
+	IsLocked    bool    `json:"is_locked"`
+	Billable    bool    `json:"billable"`
+	StartedTime string  `json:"started_time"`
+	EndedTime   string  `json:"ended_time"`
+	Project     Project `json:"project"`
+	Task        Task    `json:"task"`
+}
+
+func NewHarvest(config *HarvestConfig) *Harvest {
+	return &Harvest{
+		Config: config,
+		client: httpretry.ClientOrDefault(config.HTTPClient),
+	}
+}
+
+func (h *Harvest) GetWorkspaceID(ctx context.Context) (string, error) {
+	var me interface{}
+	if err := h.doRequest(ctx, http.MethodGet, "users/me", nil, &me); err != nil {
+		return "", err
+	}
+
+	formattedBytes, err := json.MarshalIndent(me, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(formattedBytes), nil
+}
+
+// GetProjectAssignments returns the projects the user can log time to together with their tasks.
+func (h *Harvest) GetProjectAssignments(ctx context.Context) ([]ProjectAssignment, error) {
+	if h.projectAssignments != nil {
+		return h.projectAssignments, nil
+	}
+
+	var assignments []ProjectAssignment
+	for page := 1; page != 0; {
+		var response struct {
+			ProjectAssignments []ProjectAssignment `json:"project_assignments"`
+			NextPage           *int                `json:"next_page"`
+		}
+		if err := h.doRequest(ctx, http.MethodGet, "users/me/project_assignments?page="+strconv.Itoa(page), nil, &response); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, response.ProjectAssignments...)
+
+		page = 0
+		if response.NextPage != nil {
+			page = *response.NextPage
+		}
+	}
+
+	h.projectAssignments = assignments
+	return assignments, nil
+}
+
+// GetTasks returns the tasks assigned to the given project.
+func (h *Harvest) GetTasks(ctx context.Context, projectID string) ([]Task, error) {
+	assignments, err := h.GetProjectAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, assignment := range assignments {
+		if strconv.FormatInt(assignment.Project.ID, 10) != projectID {
+			continue
+		}
+		tasks := make([]Task, 0, len(assignment.TaskAssignments))
+		for _, taskAssignment := range assignment.TaskAssignments {
+			tasks = append(tasks, taskAssignment.Task)
+		}
+		return tasks, nil
+	}
+
+	return nil, fmt.Errorf("project %s is not assigned to you in Harvest", projectID)
+}
+
+// ListProjects returns the projects assigned to the user.
+func (h *Harvest) ListProjects(ctx context.Context) ([]tracker.Project, error) {
+	assignments, err := h.GetProjectAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]tracker.Project, 0, len(assignments))
+	for _, assignment := range assignments {
+		projects = append(projects, tracker.Project{ID: strconv.FormatInt(assignment.Project.ID, 10), Name: assignment.Project.Name})
+	}
+	return projects, nil
+}
+
+func (h *Harvest) LogTime(ctx context.Context, te *tracker.TimeEntry) (string, error) {
+	body, err := h.entryBody(ctx, te)
+	if err != nil {
+		return "", err
+	}
+
+	var created timeEntry
+	if err := h.doRequest(ctx, http.MethodPost, "time_entries", body, &created); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(created.ID, 10), nil
+}
+
+func (h *Harvest) EditLog(ctx context.Context, ID string, te *tracker.TimeEntry) error {
+	body, err := h.entryBody(ctx, te)
+	if err != nil {
+		return err
+	}
+
+	return h.doRequest(ctx, http.MethodPatch, "time_entries/"+ID, body, nil)
+}
+
+func (h *Harvest) DeleteLog(ctx context.Context, ID string) error {
+	return h.doRequest(ctx, http.MethodDelete, "time_entries/"+ID, nil, nil)
+}
+
+func (h *Harvest) GetReport(ctx context.Context, from time.Time, to time.Time) ([]tracker.ReportTimeEntry, error) {
+	userID, err := h.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reportEntries []tracker.ReportTimeEntry
+	for page := 1; page != 0; {
+		query := url.Values{}
+		query.Set("user_id", strconv.FormatInt(userID, 10))
+		query.Set("from", from.Format(time.DateOnly))
+		query.Set("to", to.Format(time.DateOnly))
+		query.Set("page", strconv.Itoa(page))
+
+		var response struct {
+			TimeEntries []timeEntry `json:"time_entries"`
+			NextPage    *int        `json:"next_page"`
+		}
+		if err := h.doRequest(ctx, http.MethodGet, "time_entries?"+query.Encode(), nil, &response); err != nil {
+			return nil, err
+		}
+
+		for _, entry := range response.TimeEntries {
+			reportEntry, err := toReportTimeEntry(&entry)
+			if err != nil {
+				return nil, err
+			}
+			reportEntries = append(reportEntries, reportEntry)
+		}
+
+		page = 0
+		if response.NextPage != nil {
+			page = *response.NextPage
+		}
+	}
+
+	return reportEntries, nil
+}
+
+func (h *Harvest) getUserID(ctx context.Context) (int64, error) {
+	if h.userID != 0 {
+		return h.userID, nil
+	}
+
+	var me struct {
+		ID int64 `json:"id"`
+	}
+	if err := h.doRequest(ctx, http.MethodGet, "users/me", nil, &me); err != nil {
+		return 0, err
+	}
+
+	h.userID = me.ID
+	return me.ID, nil
+}
+
+// findTask resolves the task name to the ID of a task assigned to the project, ignoring case.
+func (h *Harvest) findTask(ctx context.Context, projectID string, taskName string) (int64, error) {
+	tasks, err := h.GetTasks(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+
+	names := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if strings.EqualFold(task.Name, taskName) {
+			return task.ID, nil
+		}
+		names = append(names, task.Name)
+	}
+
+	return 0, fmt.Errorf("task '%s' not found in Harvest project %s, available tasks: %s", taskName, projectID, strings.Join(names, ", "))
+}
+
+func (h *Harvest) entryBody(ctx context.Context, te *tracker.TimeEntry) (map[string]interface{}, error) {
+	projectID, err := strconv.ParseInt(te.ProjectID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid harvest project ID '%s': %v", te.ProjectID, err)
+	}
+
+	taskID, err := h.findTask(ctx, te.ProjectID, te.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := te.Interval()
+	start, end = start.Local(), end.Local()
+
+	body := map[string]interface{}{
+		"project_id": projectID,
+		"task_id":    taskID,
+		"spent_date": start.Format(time.DateOnly),
+		"hours":      math.Round(end.Sub(start).Hours()*100) / 100,
+	}
+	// Accounts tracking timestamps keep the start and end, the others use the hours. An entry ending on the next day
+	// cannot have times as Harvest keeps them within the spent date.
+	if end.Format(time.DateOnly) == start.Format(time.DateOnly) {
+		body["started_time"] = start.Format(timeOfDayLayout)
+		body["ended_time"] = end.Format(timeOfDayLayout)
+	}
+	return body, nil
+}
+
+func (h *Harvest) doRequest(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, h.Config.BaseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+h.Config.AccessToken)
+	req.Header.Set("Harvest-Account-Id", h.Config.AccountID)
+	req.Header.Set("User-Agent", userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return apierror.New("harvest", resp, bodyBytes)
+	}
+
+	if out == nil || len(bodyBytes) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(bodyBytes, out); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	return nil
+}
+
+// toReportTimeEntry converts the Harvest entry, the task name is used as the description. Entries of accounts
+// tracking durations only start at midnight of the spent date.
+func toReportTimeEntry(entry *timeEntry) (tracker.ReportTimeEntry, error) {
+	var reportEntry tracker.ReportTimeEntry
+
+	day, err := time.ParseInLocation(time.DateOnly, entry.SpentDate, time.Local)
+	if err != nil {
+		return reportEntry, fmt.Errorf("invalid spent date '%s' of harvest entry %d: %v", entry.SpentDate, entry.ID, err)
+	}
+
+	start := day
+	if entry.StartedTime != "" {
+		if t, err := time.Parse(timeOfDayLayout, entry.StartedTime); err == nil {
+			start = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+		}
+	}
+
+	reportEntry.ID = strconv.FormatInt(entry.ID, 10)
+	reportEntry.Description = entry.Task.Name
+	reportEntry.ProjectID = strconv.FormatInt(entry.Project.ID, 10)
+	reportEntry.TimeInterval.Start = start
+	reportEntry.TimeInterval.End = start.Add(time.Duration(entry.Hours * float64(time.Hour)).Round(time.Minute))
+	reportEntry.Billable = entry.Billable
+	reportEntry.IsLocked = entry.IsLocked
+
+	return reportEntry, nil
+}
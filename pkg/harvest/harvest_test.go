@@ -0,0 +1,260 @@
+package harvest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/apierror"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+// newTestHarvest creates a client of a stand-in server handling the requests with handler.
+func newTestHarvest(t *testing.T, handler http.HandlerFunc) *Harvest {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewHarvest(&HarvestConfig{
+		AccessToken: "secret",
+		AccountID:   "123",
+		BaseURL:     server.URL + "/v2/",
+	})
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, body any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		t.Error(err)
+	}
+}
+
+// assignments is the project assignments response listing the Backend project with its tasks on two pages.
+func assignments(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	t.Helper()
+	switch r.URL.Query().Get("page") {
+	case "1":
+		writeJSON(t, w, http.StatusOK, map[string]any{
+			"project_assignments": []map[string]any{{
+				"project": map[string]any{"id": 7, "name": "Backend", "code": "BE"},
+				"task_assignments": []map[string]any{
+					{"task": map[string]any{"id": 70, "name": "Development"}},
+					{"task": map[string]any{"id": 71, "name": "Code review"}},
+				},
+			}},
+			"next_page": 2,
+		})
+	case "2":
+		writeJSON(t, w, http.StatusOK, map[string]any{
+			"project_assignments": []map[string]any{{
+				"project":          map[string]any{"id": 8, "name": "Frontend"},
+				"task_assignments": []map[string]any{{"task": map[string]any{"id": 80, "name": "Design"}}},
+			}},
+			"next_page": nil,
+		})
+	default:
+		t.Errorf("unexpected assignments page %s", r.URL.Query().Get("page"))
+	}
+}
+
+func TestGetReport(t *testing.T) {
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2026, time.March, 31, 0, 0, 0, 0, time.Local)
+	harvest := newTestHarvest(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Harvest-Account-Id") != "123" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		switch r.URL.Path {
+		case "/v2/users/me":
+			writeJSON(t, w, http.StatusOK, map[string]any{"id": 42})
+		case "/v2/time_entries":
+			query := r.URL.Query()
+			if query.Get("user_id") != "42" || query.Get("from") != "2026-03-01" || query.Get("to") != "2026-03-31" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			if query.Get("page") == "1" {
+				writeJSON(t, w, http.StatusOK, map[string]any{
+					"time_entries": []map[string]any{{
+						"id":           1,
+						"spent_date":   "2026-03-10",
+						"hours":        1.5,
+						"notes":        "Reviewed the merge requests",
+						"is_locked":    true,
+						"billable":     true,
+						"started_time": "9:30am",
+						"ended_time":   "11:00am",
+						"project":      map[string]any{"id": 7, "name": "Backend"},
+						"task":         map[string]any{"id": 71, "name": "Code review"},
+					}},
+					"next_page": 2,
+				})
+				return
+			}
+			// Accounts tracking durations only have no started and ended times
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"time_entries": []map[string]any{{
+					"id":         2,
+					"spent_date": "2026-03-11",
+					"hours":      0.25,
+					"project":    map[string]any{"id": 8, "name": "Frontend"},
+					"task":       map[string]any{"id": 80, "name": "Design"},
+				}},
+				"next_page": nil,
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	entries, err := harvest.GetReport(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("GetReport() failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("GetReport() returned %d entries, want 2", len(entries))
+	}
+
+	review := entries[0]
+	start := time.Date(2026, time.March, 10, 9, 30, 0, 0, time.Local)
+	if review.ID != "1" || review.Description != "Code review" || review.ProjectID != "7" || !review.Billable || !review.IsLocked {
+		t.Errorf("unexpected entry %+v", review)
+	}
+	if !review.TimeInterval.Start.Equal(start) || !review.TimeInterval.End.Equal(start.Add(90*time.Minute)) {
+		t.Errorf("entry lasts %s - %s, want 9:30 - 11:00", review.TimeInterval.Start, review.TimeInterval.End)
+	}
+
+	design := entries[1]
+	midnight := time.Date(2026, time.March, 11, 0, 0, 0, 0, time.Local)
+	if design.Description != "Design" || design.ProjectID != "8" || !design.TimeInterval.Start.Equal(midnight) || design.Duration() != 15*time.Minute {
+		t.Errorf("unexpected entry %+v, want 15 minutes of Design from midnight", design)
+	}
+}
+
+func TestProjectsAndTasks(t *testing.T) {
+	var created map[string]any
+	assignmentRequests := 0
+	harvest := newTestHarvest(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/users/me/project_assignments":
+			assignmentRequests++
+			assignments(t, w, r)
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/time_entries":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Error(err)
+			}
+			writeJSON(t, w, http.StatusCreated, map[string]any{"id": 99})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	projects, err := harvest.ListProjects(context.Background())
+	if err != nil {
+		t.Fatalf("ListProjects() failed: %v", err)
+	}
+	want := []tracker.Project{{ID: "7", Name: "Backend"}, {ID: "8", Name: "Frontend"}}
+	if len(projects) != len(want) || projects[0] != want[0] || projects[1] != want[1] {
+		t.Errorf("ListProjects() = %v, want %v", projects, want)
+	}
+
+	tasks, err := harvest.GetTasks(context.Background(), "7")
+	if err != nil || len(tasks) != 2 || tasks[1].ID != 71 || tasks[1].Name != "Code review" {
+		t.Errorf("GetTasks() = %v, %v, want Development and Code review", tasks, err)
+	}
+	if _, err := harvest.GetTasks(context.Background(), "9"); err == nil {
+		t.Error("GetTasks() of a project that is not assigned must fail")
+	}
+
+	// The description names the task, ignoring case
+	end := time.Date(2026, time.March, 10, 11, 0, 0, 0, time.Local)
+	id, err := harvest.LogTime(context.Background(), &tracker.TimeEntry{
+		Time:        end,
+		Duration:    90 * time.Minute,
+		Description: "code REVIEW",
+		ProjectID:   "7",
+		Exact:       true,
+	})
+	if err != nil {
+		t.Fatalf("LogTime() failed: %v", err)
+	}
+	if id != "99" {
+		t.Errorf("LogTime() = %s, want 99", id)
+	}
+	if created["project_id"] != float64(7) || created["task_id"] != float64(71) || created["spent_date"] != "2026-03-10" ||
+		created["hours"] != 1.5 || created["started_time"] != "9:30am" || created["ended_time"] != "11:00am" {
+		t.Errorf("unexpected entry body %v", created)
+	}
+
+	_, err = harvest.LogTime(context.Background(), &tracker.TimeEntry{Time: end, Duration: time.Hour, Description: "Meeting", ProjectID: "7"})
+	if err == nil || !strings.Contains(err.Error(), "available tasks: Development, Code review") {
+		t.Errorf("LogTime() = %v, want the available tasks", err)
+	}
+	if assignmentRequests != 2 {
+		t.Errorf("assignments were fetched with %d requests, want the two pages once", assignmentRequests)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		check   func(error) bool
+		code    string
+		message string
+	}{
+		{
+			name:    "unauthorized",
+			status:  http.StatusUnauthorized,
+			body:    `{"error":"invalid_token","error_description":"The access token provided is expired, revoked, malformed or invalid for other reasons."}`,
+			check:   apierror.IsUnauthorized,
+			code:    "invalid_token",
+			message: "The access token provided is expired, revoked, malformed or invalid for other reasons.",
+		},
+		{
+			name:    "validation",
+			status:  http.StatusUnprocessableEntity,
+			body:    `{"message":"Hours must be a number"}`,
+			check:   apierror.IsValidation,
+			message: "Hours must be a number",
+		},
+		{
+			name:    "locked",
+			status:  http.StatusForbidden,
+			body:    `{"message":"This time entry is locked and cannot be edited"}`,
+			check:   apierror.IsLocked,
+			message: "This time entry is locked and cannot be edited",
+		},
+		{
+			name:    "not found",
+			status:  http.StatusNotFound,
+			body:    ``,
+			check:   apierror.IsNotFound,
+			message: "Not Found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			harvest := newTestHarvest(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			err := harvest.DeleteLog(context.Background(), "1")
+			var apiErr *apierror.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("DeleteLog() = %v, want an API error", err)
+			}
+			if !tt.check(err) || apiErr.Service != "harvest" || apiErr.StatusCode != tt.status || apiErr.Code != tt.code || apiErr.Message != tt.message {
+				t.Errorf("unexpected error %+v", apiErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package holidays
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// countries are the built-in public holidays, only the nationwide ones.
+var countries = map[string]func(year int) []Day{
+	"AT": austria,
+	"CZ": czechia,
+	"DE": germany,
+	"PL": poland,
+	"US": unitedStates,
+}
+
+// Countries returns the codes of the countries with built-in public holidays.
+func Countries() []string {
+	codes := make([]string, 0, len(countries))
+	for code := range countries {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func countryRules(code string) (func(year int) []Day, error) {
+	rules, ok := countries[strings.ToUpper(code)]
+	if !ok {
+		return nil, fmt.Errorf("no built-in holidays for country '%s', use one of %s or an ICS file", code, strings.Join(Countries(), ", "))
+	}
+	return rules, nil
+}
+
+func czechia(year int) []Day {
+	easter := easterSunday(year)
+	return []Day{
+		fixed(year, time.January, 1, "Restoration Day of the Independent Czech State"),
+		holiday(easter.AddDate(0, 0, -2), "Good Friday"),
+		holiday(easter.AddDate(0, 0, 1), "Easter Monday"),
+		fixed(year, time.May, 1, "Labour Day"),
+		fixed(year, time.May, 8, "Liberation Day"),
+		fixed(year, time.July, 5, "Saints Cyril and Methodius Day"),
+		fixed(year, time.July, 6, "Jan Hus Day"),
+		fixed(year, time.September, 28, "St. Wenceslas Day"),
+		fixed(year, time.October, 28, "Independent Czechoslovak State Day"),
+		fixed(year, time.November, 17, "Struggle for Freedom and Democracy Day"),
+		fixed(year, time.December, 24, "Christmas Eve"),
+		fixed(year, time.December, 25, "Christmas Day"),
+		fixed(year, time.December, 26, "St. Stephen's Day"),
+	}
+}
+
+func germany(year int) []Day {
+	easter := easterSunday(year)
+	return []Day{
+		fixed(year, time.January, 1, "New Year's Day"),
+		holiday(easter.AddDate(0, 0, -2), "Good Friday"),
+		holiday(easter.AddDate(0, 0, 1), "Easter Monday"),
+		fixed(year, time.May, 1, "Labour Day"),
+		holiday(easter.AddDate(0, 0, 39), "Ascension Day"),
+		holiday(easter.AddDate(0, 0, 50), "Whit Monday"),
+		fixed(year, time.October, 3, "German Unity Day"),
+		fixed(year, time.December, 25, "Christmas Day"),
+		fixed(year, time.December, 26, "Second Day of Christmas"),
+	}
+}
+
+func austria(year int) []Day {
+	easter := easterSunday(year)
+	return []Day{
+		fixed(year, time.January, 1, "New Year's Day"),
+		fixed(year, time.January, 6, "Epiphany"),
+		holiday(easter.AddDate(0, 0, 1), "Easter Monday"),
+		fixed(year, time.May, 1, "National Holiday"),
+		holiday(easter.AddDate(0, 0, 39), "Ascension Day"),
+		holiday(easter.AddDate(0, 0, 50), "Whit Monday"),
+		holiday(easter.AddDate(0, 0, 60), "Corpus Christi"),
+		fixed(year, time.August, 15, "Assumption Day"),
+		fixed(year, time.October, 26, "National Day"),
+		fixed(year, time.November, 1, "All Saints' Day"),
+		fixed(year, time.December, 8, "Immaculate Conception"),
+		fixed(year, time.December, 25, "Christmas Day"),
+		fixed(year, time.December, 26, "St. Stephen's Day"),
+	}
+}
+
+func poland(year int) []Day {
+	easter := easterSunday(year)
+	days := []Day{
+		fixed(year, time.January, 1, "New Year's Day"),
+		fixed(year, time.January, 6, "Epiphany"),
+		holiday(easter, "Easter Sunday"),
+		holiday(easter.AddDate(0, 0, 1), "Easter Monday"),
+		fixed(year, time.May, 1, "Labour Day"),
+		fixed(year, time.May, 3, "Constitution Day"),
+		holiday(easter.AddDate(0, 0, 49), "Pentecost Sunday"),
+		holiday(easter.AddDate(0, 0, 60), "Corpus Christi"),
+		fixed(year, time.August, 15, "Assumption Day"),
+		fixed(year, time.November, 1, "All Saints' Day"),
+		fixed(year, time.November, 11, "Independence Day"),
+		fixed(year, time.December, 25, "Christmas Day"),
+		fixed(year, time.December, 26, "Second Day of Christmas"),
+	}
+	if year >= 2025 {
+		days = append(days, fixed(year, time.December, 24, "Christmas Eve"))
+	}
+	return days
+}
+
+// unitedStates returns the federal holidays, those falling on a weekend are observed on the closest weekday.
+func unitedStates(year int) []Day {
+	days := []Day{
+		observed(fixed(year, time.January, 1, "New Year's Day")),
+		holiday(nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King Jr. Day"),
+		holiday(nthWeekday(year, time.February, time.Monday, 3), "Washington's Birthday"),
+		holiday(nthWeekday(year, time.June, time.Monday, 1).AddDate(0, 0, -7), "Memorial Day"),
+		observed(fixed(year, time.July, 4, "Independence Day")),
+		holiday(nthWeekday(year, time.September, time.Monday, 1), "Labor Day"),
+		holiday(nthWeekday(year, time.October, time.Monday, 2), "Columbus Day"),
+		observed(fixed(year, time.November, 11, "Veterans Day")),
+		holiday(nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day"),
+		observed(fixed(year, time.December, 25, "Christmas Day")),
+	}
+	if year >= 2021 {
+		days = append(days, observed(fixed(year, time.June, 19, "Juneteenth")))
+	}
+	return days
+}
+
+func fixed(year int, month time.Month, day int, name string) Day {
+	return holiday(time.Date(year, month, day, 0, 0, 0, 0, time.UTC), name)
+}
+
+func holiday(date time.Time, name string) Day {
+	return Day{Date: date, Name: name, Kind: PublicHoliday}
+}
+
+// observed moves a holiday falling on Saturday to Friday and on Sunday to Monday.
+func observed(day Day) Day {
+	switch day.Date.Weekday() {
+	case time.Saturday:
+		day.Date = day.Date.AddDate(0, 0, -1)
+	case time.Sunday:
+		day.Date = day.Date.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// nthWeekday returns the n-th given weekday of the month.
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+(n-1)*7)
+}
+
+// easterSunday computes the date of the Western Easter using the anonymous Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,87 @@
+package holidays
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEasterSunday(t *testing.T) {
+	tests := []time.Time{
+		day(1818, time.March, 22), // Earliest possible date
+		day(2000, time.April, 23),
+		day(2019, time.April, 21),
+		day(2024, time.March, 31),
+		day(2025, time.April, 20),
+		day(2026, time.April, 5),
+		day(2038, time.April, 25), // Latest possible date
+	}
+
+	for _, want := range tests {
+		if got := easterSunday(want.Year()); !got.Equal(want) {
+			t.Errorf("easterSunday(%d) = %s, want %s", want.Year(), got.Format(time.DateOnly), want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestCountryHolidays(t *testing.T) {
+	tests := []struct {
+		country string
+		date    time.Time
+		want    string // Empty when the date is not a holiday
+	}{
+		{"US", day(2026, time.July, 3), "Independence Day"}, // Saturday the 4th is observed on Friday
+		{"US", day(2026, time.July, 4), ""},
+		{"US", day(2022, time.December, 26), "Christmas Day"},  // Sunday the 25th is observed on Monday
+		{"US", day(2021, time.December, 31), "New Year's Day"}, // Observed in the previous year
+		{"US", day(2026, time.January, 19), "Martin Luther King Jr. Day"},
+		{"US", day(2026, time.February, 16), "Washington's Birthday"},
+		{"US", day(2026, time.May, 25), "Memorial Day"}, // Last Monday of May
+		{"US", day(2027, time.May, 31), "Memorial Day"},
+		{"US", day(2026, time.September, 7), "Labor Day"},
+		{"US", day(2026, time.October, 12), "Columbus Day"},
+		{"US", day(2026, time.November, 11), "Veterans Day"},
+		{"US", day(2025, time.November, 27), "Thanksgiving Day"},
+		{"US", day(2026, time.November, 26), "Thanksgiving Day"},
+		{"US", day(2021, time.June, 18), "Juneteenth"},
+		{"US", day(2020, time.June, 19), ""}, // Not a federal holiday before 2021
+		{"CZ", day(2026, time.April, 3), "Good Friday"},
+		{"CZ", day(2026, time.April, 6), "Easter Monday"},
+		{"DE", day(2026, time.May, 14), "Ascension Day"},
+		{"DE", day(2026, time.May, 25), "Whit Monday"},
+		{"AT", day(2026, time.June, 4), "Corpus Christi"},
+		{"PL", day(2024, time.December, 24), ""},
+		{"PL", day(2025, time.December, 24), "Christmas Eve"},
+		{"pl", day(2026, time.April, 5), "Easter Sunday"}, // Country codes are case-insensitive
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.country+" "+tt.date.Format(time.DateOnly), func(t *testing.T) {
+			calendar := NewCalendar()
+			if err := calendar.AddCountry(tt.country); err != nil {
+				t.Fatal(err)
+			}
+			holiday, ok := calendar.DayOff(tt.date)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("DayOff() = %s, want no holiday", holiday.Name)
+				}
+				return
+			}
+			if !ok || holiday.Name != tt.want || holiday.Kind != PublicHoliday {
+				t.Errorf("DayOff() = %+v, %v, want %s", holiday, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnknownCountry(t *testing.T) {
+	err := NewCalendar().AddCountry("XX")
+	if err == nil || !strings.Contains(err.Error(), "AT, CZ, DE, PL, US") {
+		t.Errorf("AddCountry() = %v, want an error listing the countries", err)
+	}
+}
@@ -0,0 +1,120 @@
+package holidays
+
+import "time"
+
+type Kind string
+
+const (
+	PublicHoliday Kind = "holiday"
+	Leave         Kind = "leave"
+)
+
+// Day is a non-working day besides the weekends.
+type Day struct {
+	Date time.Time // Midnight UTC
+	Name string
+	Kind Kind
+}
+
+// Calendar combines the public holidays of a country, the events of an ICS file and the personal leave.
+type Calendar struct {
+	rules  func(year int) []Day // Built-in rules of a country, nil without one
+	byYear map[int][]Day        // Holidays generated by the rules, by year
+	events []Day                // Holidays of the ICS file
+	yearly []Day                // Yearly recurring holidays of the ICS file, the year of their date is ignored
+	leave  map[string]Day       // By date, YYYY-MM-DD
+}
+
+func NewCalendar() *Calendar {
+	return &Calendar{
+		byYear: map[int][]Day{},
+		leave:  map[string]Day{},
+	}
+}
+
+// AddCountry adds the built-in public holidays of the country, see Countries.
+func (c *Calendar) AddCountry(code string) error {
+	rules, err := countryRules(code)
+	if err != nil {
+		return err
+	}
+	c.rules = rules
+	return nil
+}
+
+// AddEvents adds the holidays read from an ICS file.
+func (c *Calendar) AddEvents(events []Event) {
+	for _, event := range events {
+		if event.Yearly {
+			c.yearly = append(c.yearly, Day{Date: event.Date, Name: event.Name, Kind: PublicHoliday})
+			continue
+		}
+		c.events = append(c.events, Day{Date: event.Date, Name: event.Name, Kind: PublicHoliday})
+	}
+}
+
+// AddLeave adds personal days off from the first to the last day, inclusive.
+func (c *Calendar) AddLeave(first time.Time, last time.Time) {
+	for day := date(first); !day.After(date(last)); day = day.AddDate(0, 0, 1) {
+		c.leave[day.Format(time.DateOnly)] = Day{Date: day, Name: "Leave", Kind: Leave}
+	}
+}
+
+// DayOff returns the non-working day on the date, public holidays take precedence over leave. Weekends are not
+// reported. It is safe to call on a nil calendar.
+func (c *Calendar) DayOff(t time.Time) (Day, bool) {
+	if c == nil {
+		return Day{}, false
+	}
+	day := date(t)
+
+	if c.rules != nil {
+		// A holiday on New Year's Day can be observed on the last day of the previous year
+		for _, year := range []int{day.Year(), day.Year() + 1} {
+			if _, ok := c.byYear[year]; !ok {
+				c.byYear[year] = c.rules(year)
+			}
+			for _, holiday := range c.byYear[year] {
+				if holiday.Date.Equal(day) {
+					return holiday, true
+				}
+			}
+		}
+	}
+	for _, holiday := range c.events {
+		if holiday.Date.Equal(day) {
+			return holiday, true
+		}
+	}
+	for _, holiday := range c.yearly {
+		if !day.Before(holiday.Date) && holiday.Date.Month() == day.Month() && holiday.Date.Day() == day.Day() {
+			return Day{Date: day, Name: holiday.Name, Kind: holiday.Kind}, true
+		}
+	}
+	if leave, ok := c.leave[day.Format(time.DateOnly)]; ok {
+		return leave, true
+	}
+	return Day{}, false
+}
+
+// IsDayOff reports whether the date is a holiday or leave, it makes the calendar usable as tracker.DaysOff.
+func (c *Calendar) IsDayOff(t time.Time) bool {
+	_, ok := c.DayOff(t)
+	return ok
+}
+
+// Between returns the non-working days from the day of from up to and including the day of to.
+func (c *Calendar) Between(from time.Time, to time.Time) []Day {
+	var days []Day
+	for day := date(from); !day.After(date(to)); day = day.AddDate(0, 0, 1) {
+		if dayOff, ok := c.DayOff(day); ok {
+			days = append(days, dayOff)
+		}
+	}
+	return days
+}
+
+// date returns the calendar date of t at midnight UTC, so that dates compare equal regardless of the time zone.
+func date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
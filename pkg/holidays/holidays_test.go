@@ -0,0 +1,82 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendarLeave(t *testing.T) {
+	calendar := NewCalendar()
+	if err := calendar.AddCountry("CZ"); err != nil {
+		t.Fatal(err)
+	}
+	// The time of day of the range is ignored, both days are included
+	calendar.AddLeave(time.Date(2026, time.March, 30, 15, 0, 0, 0, time.Local), time.Date(2026, time.April, 3, 8, 0, 0, 0, time.Local))
+
+	var got []string
+	for _, dayOff := range calendar.Between(day(2026, time.March, 29), day(2026, time.April, 6)) {
+		got = append(got, dayOff.Date.Format(time.DateOnly)+" "+string(dayOff.Kind))
+	}
+	want := []string{
+		"2026-03-30 leave",
+		"2026-03-31 leave",
+		"2026-04-01 leave",
+		"2026-04-02 leave",
+		"2026-04-03 holiday", // Good Friday takes precedence over the leave
+		"2026-04-06 holiday",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Between() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Between()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCalendarDayOff(t *testing.T) {
+	calendar := NewCalendar()
+	calendar.AddEvents([]Event{
+		{Date: day(2026, time.August, 14), Name: "Company Day"},
+		{Date: day(2020, time.December, 31), Name: "New Year's Eve", Yearly: true},
+	})
+
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{name: "event", date: day(2026, time.August, 14), want: "Company Day"},
+		{name: "event in another time zone", date: time.Date(2026, time.August, 14, 23, 30, 0, 0, tokyo), want: "Company Day"},
+		{name: "day after the event", date: day(2026, time.August, 15)},
+		{name: "yearly event", date: day(2026, time.December, 31), want: "New Year's Eve"},
+		{name: "yearly event before its first year", date: day(2019, time.December, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dayOff, ok := calendar.DayOff(tt.date)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("DayOff() = %s, want a working day", dayOff.Name)
+				}
+				return
+			}
+			if !ok || dayOff.Name != tt.want {
+				t.Errorf("DayOff() = %+v, %v, want %s", dayOff, ok, tt.want)
+			}
+			if !dayOff.Date.Equal(day(tt.date.Year(), tt.date.Month(), tt.date.Day())) {
+				t.Errorf("DayOff() is on %s, want the date asked for", dayOff.Date)
+			}
+		})
+	}
+}
+
+func TestNilCalendar(t *testing.T) {
+	var calendar *Calendar
+	if calendar.IsDayOff(day(2026, time.December, 25)) {
+		t.Error("a nil calendar has a day off")
+	}
+}
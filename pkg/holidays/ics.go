@@ -0,0 +1,130 @@
+package holidays
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Event is an all-day event of an ICS file, events spanning several days are split into one event per day.
+type Event struct {
+	Date   time.Time // Midnight UTC
+	Name   string
+	Yearly bool // Repeats every year from Date on
+}
+
+// LoadICS reads the events of an ICS file.
+func LoadICS(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events, err := ParseICS(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return events, nil
+}
+
+// ParseICS reads the events of an iCalendar stream. Only the start, end, summary and a yearly repetition of the events
+// are used, which is what holiday calendars contain.
+func ParseICS(r io.Reader) ([]Event, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var start, end time.Time
+	var name string
+	var yearly, inEvent bool
+	for number, line := range lines {
+		property, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Parameters such as ;VALUE=DATE follow the name of the property
+		property, _, _ = strings.Cut(strings.ToUpper(property), ";")
+
+		switch property {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent = true
+				start, end, name, yearly = time.Time{}, time.Time{}, "", false
+			}
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || !inEvent {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				return nil, fmt.Errorf("line %d: event '%s' has no start", number+1, name)
+			}
+			if end.IsZero() || !end.After(start) {
+				// All-day events without an end last a single day
+				end = start.AddDate(0, 0, 1)
+			}
+			for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+				events = append(events, Event{Date: day, Name: name, Yearly: yearly})
+			}
+		case "DTSTART", "DTEND":
+			if !inEvent {
+				continue
+			}
+			date, err := parseICSDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", number+1, err)
+			}
+			if property == "DTSTART" {
+				start = date
+			} else {
+				end = date
+			}
+		case "SUMMARY":
+			if inEvent {
+				name = unescapeText(value)
+			}
+		case "RRULE":
+			if inEvent && strings.Contains(strings.ToUpper(value), "FREQ=YEARLY") {
+				yearly = true
+			}
+		}
+	}
+	return events, nil
+}
+
+// unfoldLines joins the lines continued by a leading space or tab.
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseICSDate returns the date of a DATE or DATE-TIME value, the time of day is ignored.
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < len("20060102") {
+		return time.Time{}, fmt.Errorf("invalid date '%s'", value)
+	}
+	date, err := time.Parse("20060102", value[:len("20060102")])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s'", value)
+	}
+	return date, nil
+}
+
+func unescapeText(value string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,72 @@
+package holidays
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseICS(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"SUMMARY:Not an event",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20261224",
+		"SUMMARY:Christmas\\, closed",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20261228",
+		"DTEND;VALUE=DATE:20261231",
+		"SUMMARY:Company",
+		"  shutdown",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20260501T000000Z",
+		"DTEND:20260501T235959Z",
+		"SUMMARY:Labour Day",
+		"RRULE:FREQ=YEARLY;BYMONTH=5",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := ParseICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("ParseICS() failed: %v", err)
+	}
+
+	want := []Event{
+		{Date: day(2026, time.December, 24), Name: "Christmas, closed"}, // No end, a single day
+		{Date: day(2026, time.December, 28), Name: "Company shutdown"},  // The end is exclusive
+		{Date: day(2026, time.December, 29), Name: "Company shutdown"},
+		{Date: day(2026, time.December, 30), Name: "Company shutdown"},
+		{Date: day(2026, time.May, 1), Name: "Labour Day", Yearly: true}, // Ends on the same day
+	}
+	if len(events) != len(want) {
+		t.Fatalf("ParseICS() = %+v, want %+v", events, want)
+	}
+	for i := range want {
+		if !events[i].Date.Equal(want[i].Date) || events[i].Name != want[i].Name || events[i].Yearly != want[i].Yearly {
+			t.Errorf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
+
+func TestParseICSErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ics     string
+		wantErr string
+	}{
+		{name: "no start", ics: "BEGIN:VEVENT\nSUMMARY:Holiday\nEND:VEVENT\n", wantErr: "line 3: event 'Holiday' has no start"},
+		{name: "invalid date", ics: "BEGIN:VEVENT\nDTSTART:2026-12-24\nEND:VEVENT\n", wantErr: "line 2: invalid date '2026-12-24'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseICS(strings.NewReader(tt.ics))
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ParseICS() = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
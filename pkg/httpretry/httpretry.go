@@ -0,0 +1,204 @@
+package httpretry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 4
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+	defaultTimeout    = 2 * time.Minute // Timeout of a single request including its retries
+)
+
+type observerKey struct{}
+
+// RetryEvent describes a failed attempt that is going to be retried.
+type RetryEvent struct {
+	Method     string
+	Host       string
+	Attempt    int // Number of the upcoming retry, starting at 1
+	MaxRetries int
+	Wait       time.Duration
+	StatusCode int   // Zero when the request failed without a response
+	Err        error // Set when the request failed without a response
+}
+
+func (e RetryEvent) String() string {
+	reason := http.StatusText(e.StatusCode)
+	if e.Err != nil {
+		reason = e.Err.Error()
+	}
+	return fmt.Sprintf("%s %s failed (%s), retry %d/%d in %s", e.Method, e.Host, reason, e.Attempt, e.MaxRetries, e.Wait.Round(time.Millisecond))
+}
+
+// Transport retries requests rejected by rate limiting (429) and idempotent requests failing with a network error
+// or a temporary server error, waiting with exponential backoff or as long as the Retry-After header says.
+type Transport struct {
+	Base       http.RoundTripper
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{
+		Base:       base,
+		MaxRetries: defaultMaxRetries,
+		MinBackoff: defaultMinBackoff,
+		MaxBackoff: defaultMaxBackoff,
+	}
+}
+
+// NewClient creates a client that retries rate limited and failed requests.
+func NewClient() *http.Client {
+	return &http.Client{
+		Timeout:   defaultTimeout,
+		Transport: NewTransport(http.DefaultTransport),
+	}
+}
+
+// ClientOrDefault returns the client, or a new one created by NewClient when it is nil.
+func ClientOrDefault(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return NewClient()
+}
+
+// WithObserver returns a context that reports the retries of requests made with it to fn.
+func WithObserver(ctx context.Context, fn func(RetryEvent)) context.Context {
+	return context.WithValue(ctx, observerKey{}, fn)
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			var err error
+			if attemptReq, err = rewind(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := base.RoundTrip(attemptReq)
+		if attempt >= t.MaxRetries || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		// A body that cannot be sent again means the request cannot be retried
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		event := RetryEvent{
+			Method:     req.Method,
+			Host:       req.URL.Host,
+			Attempt:    attempt + 1,
+			MaxRetries: t.MaxRetries,
+			Wait:       wait,
+			Err:        err,
+		}
+		if resp != nil {
+			event.StatusCode = resp.StatusCode
+			// Drain the body so that the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if observer, ok := req.Context().Value(observerKey{}).(func(RetryEvent)); ok {
+			observer(event)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *Transport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	// Rate limited requests were not processed, so it is safe to send them again regardless of the method
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !isIdempotent(req.Method) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *Transport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(wait, t.MaxBackoff)
+		}
+	}
+
+	wait := t.MinBackoff << attempt
+	if wait <= 0 || wait > t.MaxBackoff {
+		wait = t.MaxBackoff
+	}
+	// Add up to 20 % of jitter so that concurrent requests do not retry at the same moment
+	return wait + rand.N(wait/5+1)
+}
+
+// retryAfter parses the Retry-After header, which holds either a number of seconds or an HTTP date.
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
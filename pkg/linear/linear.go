@@ -2,20 +2,25 @@ package linear
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/apierror"
+	"github.com/andrejsoucek/chronos/pkg/httpretry"
 )
 
 type LinearConfig struct {
-	APIKey  string
-	BaseURL string
+	APIKey     string
+	BaseURL    string
+	HTTPClient *http.Client // Optional, httpretry.NewClient is used when nil
 }
 
 type Linear struct {
 	Config *LinearConfig
+	client *http.Client
 }
 
 type GraphQLRequest struct {
@@ -37,13 +42,24 @@ type LastActivityResponse struct {
 	} `json:"data"`
 }
 
+type Viewer struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
 func NewLinear(config *LinearConfig) *Linear {
 	return &Linear{
 		Config: config,
+		client: httpretry.ClientOrDefault(config.HTTPClient),
 	}
 }
 
-func (l *Linear) GetLastActivity(from time.Time, to time.Time) ([]LastActivityItem, error) {
+func (l *Linear) IsConfigured() bool {
+	return l.Config.APIKey != "" && l.Config.BaseURL != ""
+}
+
+func (l *Linear) GetLastActivity(ctx context.Context, from time.Time, to time.Time) ([]LastActivityItem, error) {
 	graphqlQuery := `
 	query myRecentIssueActivity {
 		issues(
@@ -84,42 +100,71 @@ func (l *Linear) GetLastActivity(from time.Time, to time.Time) ([]LastActivityIt
 	}
 	`
 
+	var response LastActivityResponse
+	if err := l.query(ctx, graphqlQuery, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Data.Issues.Nodes, nil
+}
+
+// GetViewer returns the user the API key belongs to.
+func (l *Linear) GetViewer(ctx context.Context) (*Viewer, error) {
+	var response struct {
+		Data struct {
+			Viewer Viewer `json:"viewer"`
+		} `json:"data"`
+	}
+	if err := l.query(ctx, `query { viewer { id name email } }`, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Data.Viewer, nil
+}
+
+// query sends the GraphQL query and unmarshals the whole response into out.
+func (l *Linear) query(ctx context.Context, graphqlQuery string, out interface{}) error {
 	requestBody := GraphQLRequest{
 		Query: graphqlQuery,
 	}
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, l.Config.BaseURL, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.Config.BaseURL, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", l.Config.APIKey)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := l.client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status code %d: %s", resp.StatusCode, string(body))
+		return apierror.New("linear", resp, body)
 	}
 
-	var response LastActivityResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, err
+	// GraphQL reports failed queries in the errors field of a successful response
+	var errorsResponse struct {
+		Errors []json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &errorsResponse); err != nil {
+		return err
+	}
+	if len(errorsResponse.Errors) > 0 {
+		return apierror.New("linear", resp, body)
 	}
 
-	return response.Data.Issues.Nodes, nil
+	return json.Unmarshal(body, out)
 }
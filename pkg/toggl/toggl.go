@@ -0,0 +1,334 @@
+package toggl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/apierror"
+	"github.com/andrejsoucek/chronos/pkg/httpretry"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+const createdWith = "chronos"
+
+type TogglConfig struct {
+	APIKey      string
+	BaseURL     string
+	WorkspaceID string
+	HTTPClient  *http.Client // Optional, httpretry.NewClient is used when nil
+}
+
+type Toggl struct {
+	Config *TogglConfig
+	client *http.Client
+}
+
+type Workspace struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type Project struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+type timeEntry struct {
+	ID          int64      `json:"id"`
+	WorkspaceID int64      `json:"workspace_id"`
+	ProjectID   *int64     `json:"project_id"`
+	TaskID      *int64     `json:"task_id"`
+	TagIDs      []int64    `json:"tag_ids"`
+	Billable    bool       `json:"billable"`
+	Description string     `json:"description"`
+	Start       time.Time  `json:"start"`
+	Stop        *time.Time `json:"stop"`
+	Duration    int64      `json:"duration"` // Negative for running entries
+}
+
+func NewToggl(config *TogglConfig) *Toggl {
+	return &Toggl{
+		Config: config,
+		client: httpretry.ClientOrDefault(config.HTTPClient),
+	}
+}
+
+func (t *Toggl) GetWorkspaceID(ctx context.Context) (string, error) {
+	var me interface{}
+	if err := t.doRequest(ctx, http.MethodGet, "me", nil, &me); err != nil {
+		return "", err
+	}
+
+	formattedBytes, err := json.MarshalIndent(me, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(formattedBytes), nil
+}
+
+func (t *Toggl) GetWorkspaces(ctx context.Context) ([]Workspace, error) {
+	var workspaces []Workspace
+	if err := t.doRequest(ctx, http.MethodGet, "me/workspaces", nil, &workspaces); err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+func (t *Toggl) GetProjects(ctx context.Context) ([]Project, error) {
+	var projects []Project
+	if err := t.doRequest(ctx, http.MethodGet, "workspaces/"+t.Config.WorkspaceID+"/projects", nil, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// ListProjects returns the active projects of the workspace.
+func (t *Toggl) ListProjects(ctx context.Context) ([]tracker.Project, error) {
+	projects, err := t.GetProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []tracker.Project
+	for _, project := range projects {
+		if project.Active {
+			list = append(list, tracker.Project{ID: strconv.FormatInt(project.ID, 10), Name: project.Name})
+		}
+	}
+	return list, nil
+}
+
+func (t *Toggl) LogTime(ctx context.Context, te *tracker.TimeEntry) (string, error) {
+	start, end := te.Interval()
+	body, err := t.entryBody(te, start, &end)
+	if err != nil {
+		return "", err
+	}
+
+	var created timeEntry
+	if err := t.doRequest(ctx, http.MethodPost, "workspaces/"+t.Config.WorkspaceID+"/time_entries", body, &created); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(created.ID, 10), nil
+}
+
+func (t *Toggl) EditLog(ctx context.Context, ID string, te *tracker.TimeEntry) error {
+	start, end := te.Interval()
+	body, err := t.entryBody(te, start, &end)
+	if err != nil {
+		return err
+	}
+
+	return t.doRequest(ctx, http.MethodPut, "workspaces/"+t.Config.WorkspaceID+"/time_entries/"+ID, body, nil)
+}
+
+func (t *Toggl) DeleteLog(ctx context.Context, ID string) error {
+	return t.doRequest(ctx, http.MethodDelete, "workspaces/"+t.Config.WorkspaceID+"/time_entries/"+ID, nil, nil)
+}
+
+func (t *Toggl) GetReport(ctx context.Context, from time.Time, to time.Time) ([]tracker.ReportTimeEntry, error) {
+	query := url.Values{}
+	query.Set("start_date", from.Format(time.RFC3339))
+	query.Set("end_date", to.Format(time.RFC3339))
+
+	var entries []timeEntry
+	if err := t.doRequest(ctx, http.MethodGet, "me/time_entries?"+query.Encode(), nil, &entries); err != nil {
+		return nil, err
+	}
+
+	reportEntries := make([]tracker.ReportTimeEntry, 0, len(entries))
+	for _, entry := range entries {
+		reportEntries = append(reportEntries, toReportTimeEntry(&entry))
+	}
+
+	return reportEntries, nil
+}
+
+// StartTimer starts a running time entry at te.Time, the duration of the entry is ignored.
+func (t *Toggl) StartTimer(ctx context.Context, te *tracker.TimeEntry) (string, error) {
+	body, err := t.entryBody(te, te.Time, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var created timeEntry
+	if err := t.doRequest(ctx, http.MethodPost, "workspaces/"+t.Config.WorkspaceID+"/time_entries", body, &created); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(created.ID, 10), nil
+}
+
+// StopTimer stops the running time entry at the given time, Toggl stops it at the time of the request
+// so the stop is set explicitly afterwards.
+func (t *Toggl) StopTimer(ctx context.Context, end time.Time) (*tracker.ReportTimeEntry, error) {
+	running, err := t.getCurrentEntry(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stop := end.UTC()
+	running.Stop = &stop
+	running.Duration = int64(stop.Sub(running.Start).Seconds())
+
+	var stopped timeEntry
+	path := fmt.Sprintf("workspaces/%d/time_entries/%d", running.WorkspaceID, running.ID)
+	if err := t.doRequest(ctx, http.MethodPut, path, running, &stopped); err != nil {
+		return nil, err
+	}
+
+	reportEntry := toReportTimeEntry(&stopped)
+	return &reportEntry, nil
+}
+
+func (t *Toggl) GetRunningTimer(ctx context.Context) (*tracker.ReportTimeEntry, error) {
+	running, err := t.getCurrentEntry(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reportEntry := toReportTimeEntry(running)
+	return &reportEntry, nil
+}
+
+func (t *Toggl) getCurrentEntry(ctx context.Context) (*timeEntry, error) {
+	// The endpoint responds with null when no entry is running
+	var running *timeEntry
+	if err := t.doRequest(ctx, http.MethodGet, "me/time_entries/current", nil, &running); err != nil {
+		return nil, err
+	}
+	if running == nil {
+		return nil, tracker.ErrNoRunningTimer
+	}
+	return running, nil
+}
+
+func (t *Toggl) entryBody(te *tracker.TimeEntry, start time.Time, end *time.Time) (map[string]interface{}, error) {
+	workspaceID, err := strconv.ParseInt(t.Config.WorkspaceID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid toggl workspace ID '%s': %v", t.Config.WorkspaceID, err)
+	}
+
+	body := map[string]interface{}{
+		"created_with": createdWith,
+		"description":  te.Description,
+		"start":        start.UTC().Format(time.RFC3339),
+		"workspace_id": workspaceID,
+		"duration":     -1,
+	}
+
+	if end != nil {
+		body["stop"] = end.UTC().Format(time.RFC3339)
+		body["duration"] = int64(end.Sub(start).Seconds())
+	}
+
+	if te.ProjectID != "" {
+		projectID, err := strconv.ParseInt(te.ProjectID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid toggl project ID '%s': %v", te.ProjectID, err)
+		}
+		body["project_id"] = projectID
+	}
+
+	if te.TaskID != "" {
+		taskID, err := strconv.ParseInt(te.TaskID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid toggl task ID '%s': %v", te.TaskID, err)
+		}
+		body["task_id"] = taskID
+	}
+
+	if len(te.TagIDs) > 0 {
+		tagIDs := make([]int64, 0, len(te.TagIDs))
+		for _, tag := range te.TagIDs {
+			tagID, err := strconv.ParseInt(tag, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid toggl tag ID '%s': %v", tag, err)
+			}
+			tagIDs = append(tagIDs, tagID)
+		}
+		body["tag_ids"] = tagIDs
+	}
+
+	if te.Billable != nil {
+		body["billable"] = *te.Billable
+	}
+
+	return body, nil
+}
+
+func (t *Toggl) doRequest(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.Config.BaseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.Config.APIKey, "api_token")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return apierror.New("toggl", resp, bodyBytes)
+	}
+
+	if out == nil || len(bodyBytes) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(bodyBytes, out); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	return nil
+}
+
+func toReportTimeEntry(entry *timeEntry) tracker.ReportTimeEntry {
+	var reportEntry tracker.ReportTimeEntry
+	reportEntry.ID = strconv.FormatInt(entry.ID, 10)
+	reportEntry.Description = entry.Description
+	if entry.ProjectID != nil {
+		reportEntry.ProjectID = strconv.FormatInt(*entry.ProjectID, 10)
+	}
+	reportEntry.TimeInterval.Start = entry.Start
+	if entry.Stop != nil {
+		reportEntry.TimeInterval.End = *entry.Stop
+	}
+	if entry.TaskID != nil {
+		reportEntry.TaskID = strconv.FormatInt(*entry.TaskID, 10)
+	}
+	for _, tagID := range entry.TagIDs {
+		reportEntry.TagIDs = append(reportEntry.TagIDs, strconv.FormatInt(tagID, 10))
+	}
+	reportEntry.Billable = entry.Billable
+	return reportEntry
+}
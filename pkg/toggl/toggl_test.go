@@ -0,0 +1,280 @@
+package toggl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrejsoucek/chronos/pkg/apierror"
+	"github.com/andrejsoucek/chronos/pkg/tracker"
+)
+
+// newTestToggl creates a client of a stand-in server handling the requests with handler.
+func newTestToggl(t *testing.T, handler http.HandlerFunc) *Toggl {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewToggl(&TogglConfig{
+		APIKey:      "secret",
+		BaseURL:     server.URL + "/api/v9/",
+		WorkspaceID: "42",
+	})
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, body any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLogTime(t *testing.T) {
+	var got map[string]any
+	toggl := newTestToggl(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v9/workspaces/42/time_entries" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if user, password, _ := r.BasicAuth(); user != "secret" || password != "api_token" {
+			t.Errorf("unexpected credentials %s:%s", user, password)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		writeJSON(t, w, http.StatusOK, map[string]any{"id": 1001})
+	})
+
+	billable := true
+	end := time.Date(2026, time.March, 10, 10, 30, 0, 0, time.UTC)
+	id, err := toggl.LogTime(context.Background(), &tracker.TimeEntry{
+		Time:        end,
+		Duration:    90 * time.Minute,
+		Description: "Code review",
+		ProjectID:   "7",
+		TaskID:      "8",
+		TagIDs:      []string{"9", "10"},
+		Billable:    &billable,
+		Exact:       true,
+	})
+	if err != nil {
+		t.Fatalf("LogTime() failed: %v", err)
+	}
+	if id != "1001" {
+		t.Errorf("LogTime() = %s, want 1001", id)
+	}
+
+	want := map[string]any{
+		"created_with": "chronos",
+		"description":  "Code review",
+		"start":        "2026-03-10T09:00:00Z",
+		"stop":         "2026-03-10T10:30:00Z",
+		"duration":     float64(5400),
+		"workspace_id": float64(42),
+		"project_id":   float64(7),
+		"task_id":      float64(8),
+		"tag_ids":      []any{float64(9), float64(10)},
+		"billable":     true,
+	}
+	for key, value := range want {
+		if gotJSON, wantJSON := mustJSON(t, got[key]), mustJSON(t, value); gotJSON != wantJSON {
+			t.Errorf("body %s = %s, want %s", key, gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestLogTimeInvalidProject(t *testing.T) {
+	toggl := newTestToggl(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	_, err := toggl.LogTime(context.Background(), &tracker.TimeEntry{
+		Time:      time.Now(),
+		Duration:  time.Hour,
+		ProjectID: "not-a-number",
+	})
+	if err == nil {
+		t.Fatal("LogTime() succeeded, want an error for the project ID")
+	}
+}
+
+func TestGetReport(t *testing.T) {
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.March, 31, 23, 59, 59, 0, time.UTC)
+	toggl := newTestToggl(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v9/me/time_entries" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("start_date") != from.Format(time.RFC3339) || query.Get("end_date") != to.Format(time.RFC3339) {
+			t.Errorf("unexpected range %s - %s", query.Get("start_date"), query.Get("end_date"))
+		}
+		writeJSON(t, w, http.StatusOK, []map[string]any{
+			{
+				"id":          1,
+				"description": "Standup",
+				"project_id":  7,
+				"task_id":     8,
+				"tag_ids":     []int{9},
+				"billable":    true,
+				"start":       "2026-03-10T09:00:00Z",
+				"stop":        "2026-03-10T09:15:00Z",
+				"duration":    900,
+			},
+			{
+				"id":          2,
+				"description": "Running",
+				"start":       "2026-03-10T10:00:00Z",
+				"stop":        nil,
+				"duration":    -1773133200,
+			},
+		})
+	})
+
+	entries, err := toggl.GetReport(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("GetReport() failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("GetReport() returned %d entries, want 2", len(entries))
+	}
+
+	standup := entries[0]
+	if standup.ID != "1" || standup.Description != "Standup" || standup.ProjectID != "7" || standup.TaskID != "8" ||
+		len(standup.TagIDs) != 1 || standup.TagIDs[0] != "9" || !standup.Billable {
+		t.Errorf("unexpected entry %+v", standup)
+	}
+	if standup.Duration() != 15*time.Minute {
+		t.Errorf("Duration() = %s, want 15m", standup.Duration())
+	}
+
+	running := entries[1]
+	if running.ProjectID != "" || !running.TimeInterval.End.IsZero() {
+		t.Errorf("running entry %+v must have no project and no end", running)
+	}
+}
+
+func TestRunningTimer(t *testing.T) {
+	var stopped map[string]any
+	running := true
+	toggl := newTestToggl(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v9/me/time_entries/current":
+			if !running {
+				// Toggl responds with null when no entry is running
+				writeJSON(t, w, http.StatusOK, nil)
+				return
+			}
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"id":           5,
+				"workspace_id": 42,
+				"description":  "Deploy",
+				"start":        "2026-03-10T09:00:00Z",
+				"duration":     -1,
+			})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v9/workspaces/42/time_entries/5":
+			if err := json.NewDecoder(r.Body).Decode(&stopped); err != nil {
+				t.Error(err)
+			}
+			stopped["id"] = 5
+			writeJSON(t, w, http.StatusOK, stopped)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	timer, err := toggl.GetRunningTimer(context.Background())
+	if err != nil {
+		t.Fatalf("GetRunningTimer() failed: %v", err)
+	}
+	if timer.ID != "5" || timer.Description != "Deploy" {
+		t.Errorf("unexpected timer %+v", timer)
+	}
+
+	end := time.Date(2026, time.March, 10, 11, 0, 0, 0, time.UTC)
+	entry, err := toggl.StopTimer(context.Background(), end)
+	if err != nil {
+		t.Fatalf("StopTimer() failed: %v", err)
+	}
+	if !entry.TimeInterval.End.Equal(end) || stopped["duration"] != float64(7200) {
+		t.Errorf("timer stopped at %s with duration %v, want %s and 7200", entry.TimeInterval.End, stopped["duration"], end)
+	}
+
+	running = false
+	if _, err := toggl.GetRunningTimer(context.Background()); !errors.Is(err, tracker.ErrNoRunningTimer) {
+		t.Errorf("GetRunningTimer() = %v, want ErrNoRunningTimer", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		check   func(error) bool
+		message string
+	}{
+		{
+			name:    "unauthorized",
+			status:  http.StatusForbidden,
+			body:    `"Incorrect username and/or password"`,
+			check:   apierror.IsUnauthorized,
+			message: "Incorrect username and/or password",
+		},
+		{
+			name:    "validation",
+			status:  http.StatusBadRequest,
+			body:    `"Start date is required"`,
+			check:   apierror.IsValidation,
+			message: "Start date is required",
+		},
+		{
+			name:    "not found",
+			status:  http.StatusNotFound,
+			body:    ``,
+			check:   apierror.IsNotFound,
+			message: "Not Found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toggl := newTestToggl(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := toggl.GetReport(context.Background(), time.Now(), time.Now())
+			var apiErr *apierror.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetReport() = %v, want an API error", err)
+			}
+			if !tt.check(err) || apiErr.Service != "toggl" || apiErr.StatusCode != tt.status || apiErr.Message != tt.message {
+				t.Errorf("unexpected error %+v", apiErr)
+			}
+		})
+	}
+}
+
+func TestInvalidResponse(t *testing.T) {
+	toggl := newTestToggl(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"not": "a list"`))
+	})
+
+	if _, err := toggl.GetReport(context.Background(), time.Now(), time.Now()); err == nil {
+		t.Fatal("GetReport() succeeded with an invalid response")
+	}
+}
+
+func mustJSON(t *testing.T, value any) string {
+	t.Helper()
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,123 @@
+package tracker
+
+import (
+	"fmt"
+	"time"
+)
+
+type RoundingMode string
+
+const (
+	RoundNearest RoundingMode = "nearest"
+	RoundUp      RoundingMode = "up"
+	RoundDown    RoundingMode = "down"
+	RoundNone    RoundingMode = "none"
+)
+
+// RoundingTarget is the part of an entry that is rounded, the other parts follow so that the entry stays consistent.
+type RoundingTarget string
+
+const (
+	RoundStart    RoundingTarget = "start"    // The start is rounded and the duration kept
+	RoundEnd      RoundingTarget = "end"      // The end is rounded and the duration kept
+	RoundDuration RoundingTarget = "duration" // The duration is rounded and the start kept
+)
+
+// Rounding adjusts logged entries to a granularity, e.g. to bill in 6 or 15 minute increments.
+type Rounding struct {
+	Granularity time.Duration
+	Mode        RoundingMode
+	Target      RoundingTarget
+}
+
+// RoundingPolicy is the rounding of a profile, projects can use their own.
+type RoundingPolicy struct {
+	Default  *Rounding           // Nil when only the listed projects are rounded
+	Projects map[string]Rounding // By project ID
+}
+
+// For returns the rounding of the project, nil when the policy does not round its entries.
+func (p *RoundingPolicy) For(projectID string) *Rounding {
+	if p == nil {
+		return nil
+	}
+	if rounding, ok := p.Projects[projectID]; ok {
+		return &rounding
+	}
+	return p.Default
+}
+
+func (r *Rounding) Validate() error {
+	switch r.Mode {
+	case RoundNone:
+		return nil
+	case RoundNearest, RoundUp, RoundDown:
+	default:
+		return fmt.Errorf("unknown rounding mode '%s', use nearest, up, down or none", r.Mode)
+	}
+	switch r.Target {
+	case RoundStart, RoundEnd, RoundDuration:
+	default:
+		return fmt.Errorf("unknown rounding target '%s', use start, end or duration", r.Target)
+	}
+	if r.Granularity <= 0 || r.Granularity > 24*time.Hour {
+		return fmt.Errorf("rounding granularity %s must be positive and at most 24h", r.Granularity)
+	}
+	return nil
+}
+
+// Apply rounds the interval. Times are rounded on the wall clock of their day, so that e.g. 15 minute steps stay
+// aligned to the quarter hours in time zones with odd offsets, and moved by the difference only, so that they do not
+// jump by an hour on DST changes. A non-zero duration is never rounded to zero.
+func (r *Rounding) Apply(start time.Time, end time.Time) (time.Time, time.Time) {
+	if r.Mode == RoundNone || r.Granularity <= 0 {
+		return start, end
+	}
+
+	duration := end.Sub(start)
+	switch r.Target {
+	case RoundStart:
+		start = r.roundTime(start)
+		return start, start.Add(duration)
+	case RoundDuration:
+		rounded := r.roundDuration(duration)
+		if rounded == 0 && duration > 0 {
+			rounded = r.Granularity
+		}
+		return start, start.Add(rounded)
+	default:
+		end = r.roundTime(end)
+		return end.Add(-duration), end
+	}
+}
+
+// roundTime rounds the time of day, rounding up past the last step of the day moves to the next midnight.
+func (r *Rounding) roundTime(t time.Time) time.Time {
+	hour, minute, second := t.Clock()
+	sinceMidnight := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second + time.Duration(t.Nanosecond())
+	return t.Add(r.roundDuration(sinceMidnight) - sinceMidnight)
+}
+
+func (r *Rounding) roundDuration(d time.Duration) time.Duration {
+	remainder := d % r.Granularity
+	if remainder == 0 {
+		return d
+	}
+	if remainder < 0 {
+		remainder += r.Granularity
+	}
+
+	down := d - remainder
+	switch r.Mode {
+	case RoundUp:
+		return down + r.Granularity
+	case RoundDown:
+		return down
+	default:
+		if remainder*2 >= r.Granularity {
+			return down + r.Granularity
+		}
+		return down
+	}
+}
@@ -0,0 +1,127 @@
+package tracker
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestRoundingApply(t *testing.T) {
+	prague, err := time.LoadLocation("Europe/Prague")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, prague)
+	}
+	// 02:40 CEST on the fall-back day, the same wall clock time repeats an hour later in CET
+	fallBack := time.Date(2026, time.October, 25, 0, 40, 0, 0, time.UTC).In(prague)
+
+	tests := []struct {
+		name      string
+		rounding  Rounding
+		start     time.Time
+		end       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "end to nearest",
+			rounding:  Rounding{Granularity: 15 * time.Minute, Mode: RoundNearest, Target: RoundEnd},
+			start:     at(2026, time.March, 10, 9, 0),
+			end:       at(2026, time.March, 10, 10, 8),
+			wantStart: at(2026, time.March, 10, 9, 7),
+			wantEnd:   at(2026, time.March, 10, 10, 15),
+		},
+		{
+			name:      "start down",
+			rounding:  Rounding{Granularity: 15 * time.Minute, Mode: RoundDown, Target: RoundStart},
+			start:     at(2026, time.March, 10, 9, 14),
+			end:       at(2026, time.March, 10, 10, 14),
+			wantStart: at(2026, time.March, 10, 9, 0),
+			wantEnd:   at(2026, time.March, 10, 10, 0),
+		},
+		{
+			name:      "duration up",
+			rounding:  Rounding{Granularity: 6 * time.Minute, Mode: RoundUp, Target: RoundDuration},
+			start:     at(2026, time.March, 10, 9, 0),
+			end:       at(2026, time.March, 10, 9, 31),
+			wantStart: at(2026, time.March, 10, 9, 0),
+			wantEnd:   at(2026, time.March, 10, 9, 36),
+		},
+		{
+			name:      "duration is never rounded to zero",
+			rounding:  Rounding{Granularity: 15 * time.Minute, Mode: RoundDown, Target: RoundDuration},
+			start:     at(2026, time.March, 10, 9, 0),
+			end:       at(2026, time.March, 10, 9, 5),
+			wantStart: at(2026, time.March, 10, 9, 0),
+			wantEnd:   at(2026, time.March, 10, 9, 15),
+		},
+		{
+			name:      "end up past midnight",
+			rounding:  Rounding{Granularity: 15 * time.Minute, Mode: RoundUp, Target: RoundEnd},
+			start:     at(2026, time.March, 10, 23, 0),
+			end:       at(2026, time.March, 10, 23, 50),
+			wantStart: at(2026, time.March, 10, 23, 10),
+			wantEnd:   at(2026, time.March, 11, 0, 0),
+		},
+		{
+			name:      "start down to midnight",
+			rounding:  Rounding{Granularity: 30 * time.Minute, Mode: RoundDown, Target: RoundStart},
+			start:     at(2026, time.March, 11, 0, 10),
+			end:       at(2026, time.March, 11, 1, 10),
+			wantStart: at(2026, time.March, 11, 0, 0),
+			wantEnd:   at(2026, time.March, 11, 1, 0),
+		},
+		{
+			name:      "end on the fall-back day",
+			rounding:  Rounding{Granularity: 15 * time.Minute, Mode: RoundNearest, Target: RoundEnd},
+			start:     fallBack.Add(-time.Hour),
+			end:       fallBack,
+			wantStart: fallBack.Add(-55 * time.Minute),
+			wantEnd:   fallBack.Add(5 * time.Minute),
+		},
+		{
+			name:      "end after the spring-forward gap",
+			rounding:  Rounding{Granularity: 15 * time.Minute, Mode: RoundNearest, Target: RoundEnd},
+			start:     at(2026, time.March, 29, 1, 30),
+			end:       at(2026, time.March, 29, 3, 5),
+			wantStart: at(2026, time.March, 29, 1, 25),
+			wantEnd:   at(2026, time.March, 29, 3, 0),
+		},
+		{
+			name:      "none keeps the interval",
+			rounding:  Rounding{Mode: RoundNone},
+			start:     at(2026, time.March, 10, 9, 1),
+			end:       at(2026, time.March, 10, 9, 2),
+			wantStart: at(2026, time.March, 10, 9, 1),
+			wantEnd:   at(2026, time.March, 10, 9, 2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.rounding.Apply(tt.start, tt.end)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("Apply() = %s - %s, want %s - %s", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestRoundingPolicyFor(t *testing.T) {
+	var nilPolicy *RoundingPolicy
+	if nilPolicy.For("p1") != nil {
+		t.Error("nil policy must not round")
+	}
+
+	projectOnly := &RoundingPolicy{Projects: map[string]Rounding{
+		"p1": {Granularity: 6 * time.Minute, Mode: RoundUp, Target: RoundDuration},
+	}}
+	if rounding := projectOnly.For("p1"); rounding == nil || rounding.Granularity != 6*time.Minute {
+		t.Errorf("For(p1) = %v, want the project rounding", rounding)
+	}
+	if rounding := projectOnly.For("p2"); rounding != nil {
+		t.Errorf("For(p2) = %v, want nil without a default", rounding)
+	}
+}
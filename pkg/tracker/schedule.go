@@ -0,0 +1,71 @@
+package tracker
+
+import "time"
+
+// Schedule is the working time expected on every weekday, e.g. 8h from Monday to Friday.
+type Schedule struct {
+	Weekdays [7]time.Duration // Indexed by time.Weekday
+	DaysOff  DaysOff          // Nothing is expected on these days, nil when there are none
+}
+
+// DaysOff reports the non-working days besides the weekends, e.g. public holidays and leave.
+type DaysOff interface {
+	IsDayOff(day time.Time) bool
+}
+
+// Balance compares the time logged in a range of days with the time expected by the schedule.
+type Balance struct {
+	From     time.Time
+	To       time.Time
+	Logged   time.Duration
+	Expected time.Duration
+}
+
+// Difference is the overtime when positive and the deficit when negative.
+func (b Balance) Difference() time.Duration {
+	return b.Logged - b.Expected
+}
+
+// For returns the time expected on the day, zero without a schedule.
+func (s *Schedule) For(day time.Time) time.Duration {
+	if s == nil || (s.DaysOff != nil && s.DaysOff.IsDayOff(day)) {
+		return 0
+	}
+	return s.Weekdays[day.Weekday()]
+}
+
+// Expected returns the time expected from the day of from up to and including the day of to.
+func (s *Schedule) Expected(from time.Time, to time.Time) time.Duration {
+	total := time.Duration(0)
+	for day := startOfDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		total += s.For(day)
+	}
+	return total
+}
+
+// Balance compares the entries starting from the day of from up to the day of to with the schedule. The days after
+// now are left out, so the balance of the current month is the balance to date.
+func (s *Schedule) Balance(entries []ReportTimeEntry, from time.Time, to time.Time, now time.Time) Balance {
+	from = startOfDay(from)
+	if endOfToday := startOfDay(now).AddDate(0, 0, 1).Add(-time.Nanosecond); to.After(endOfToday) {
+		to = endOfToday
+	}
+
+	balance := Balance{From: from, To: to}
+	if to.Before(from) {
+		return balance
+	}
+	balance.Expected = s.Expected(from, to)
+	for _, entry := range entries {
+		start := entry.TimeInterval.Start
+		if start.Before(from) || start.After(to) {
+			continue
+		}
+		balance.Logged += entry.EndOr(now).Sub(start)
+	}
+	return balance
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
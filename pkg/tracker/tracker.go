@@ -0,0 +1,112 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrNoRunningTimer = errors.New("no timer is running")
+
+// TimeTracker is a time tracking backend the CLI and the report UI work against.
+type TimeTracker interface {
+	GetWorkspaceID(ctx context.Context) (string, error)
+	LogTime(ctx context.Context, te *TimeEntry) (string, error)
+	EditLog(ctx context.Context, ID string, te *TimeEntry) error
+	DeleteLog(ctx context.Context, ID string) error
+	GetReport(ctx context.Context, from time.Time, to time.Time) ([]ReportTimeEntry, error)
+}
+
+// Timer is implemented by backends supporting running (in-progress) time entries.
+type Timer interface {
+	StartTimer(ctx context.Context, te *TimeEntry) (string, error)
+	StopTimer(ctx context.Context, end time.Time) (*ReportTimeEntry, error)
+	GetRunningTimer(ctx context.Context) (*ReportTimeEntry, error)
+}
+
+// ProjectLister is implemented by backends able to list the projects time can be logged to.
+type ProjectLister interface {
+	ListProjects(ctx context.Context) ([]Project, error)
+}
+
+// TaskLister is implemented by backends whose entries can have a task of their project.
+type TaskLister interface {
+	ListTasks(ctx context.Context, projectID string) ([]Task, error)
+}
+
+// TagLister is implemented by backends whose entries can have tags.
+type TagLister interface {
+	ListTags(ctx context.Context) ([]Tag, error)
+}
+
+type Project struct {
+	ID   string
+	Name string
+}
+
+type Task struct {
+	ID   string
+	Name string
+}
+
+type Tag struct {
+	ID   string
+	Name string
+}
+
+type TimeEntry struct {
+	Time        time.Time // End of the entry
+	Duration    time.Duration
+	Description string
+	ProjectID   string
+	TaskID      string
+	TagIDs      []string
+	Billable    *bool     // Nil leaves the decision to the backend, e.g. the billable default of the project
+	Exact       bool      // Use Time as is instead of rounding it down to 30 minutes, unless Rounding is set
+	Rounding    *Rounding // Configured rounding of the entry, applied to exact entries as well
+}
+
+// ReportTimeEntry is a logged time entry, its JSON shape follows the Clockify API.
+type ReportTimeEntry struct {
+	ID           string `json:"id"`
+	Description  string `json:"description"`
+	ProjectID    string `json:"projectId"`
+	TimeInterval struct {
+		Start time.Time `json:"start"`
+		End   time.Time `json:"end"`
+	} `json:"timeInterval"`
+	TaskID   string   `json:"taskId"`
+	TagIDs   []string `json:"tagIds"`
+	Billable bool     `json:"billable"`
+	IsLocked bool     `json:"isLocked"`
+}
+
+// EndOr returns the end of the entry, or now for a running timer which has no end yet.
+func (e *ReportTimeEntry) EndOr(now time.Time) time.Time {
+	if e.TimeInterval.End.IsZero() {
+		return now
+	}
+	return e.TimeInterval.End
+}
+
+// Duration returns the logged time of the entry, a running timer counts the whole minutes until now.
+func (e *ReportTimeEntry) Duration() time.Duration {
+	if e.TimeInterval.End.IsZero() {
+		return time.Since(e.TimeInterval.Start).Truncate(time.Minute)
+	}
+	return e.TimeInterval.End.Sub(e.TimeInterval.Start)
+}
+
+// Interval returns the start and end time of the entry after rounding.
+func (te *TimeEntry) Interval() (time.Time, time.Time) {
+	start, end := te.Time.Add(-te.Duration), te.Time
+	switch {
+	case te.Rounding != nil:
+		return te.Rounding.Apply(start, end)
+	case te.Exact:
+		return start, end
+	default:
+		end = end.Truncate(time.Minute * 30)
+		return end.Add(-te.Duration), end
+	}
+}